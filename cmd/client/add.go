@@ -0,0 +1,86 @@
+package client
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add [file]",
+	Short: "Add content to the history",
+	Long: `Add content to the history
+The content is read from the file or from stdin and stored without touching the clipboard,
+unless clipboard selections are given. The id of the new entry is printed.
+
+The target is detected from the content if not set, png images are stored as image/png
+and any other text as UTF8_STRING.
+
+Example:
+git rev-parse HEAD | blueclip client add
+blueclip client add --important ~/.ssh/id_ed25519.pub
+blueclip client add screenshot.png -c clipboard`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		target, err := cmd.Flags().GetString("target")
+		if err != nil {
+			log.Fatalf("Failed to get target flag: %v", err)
+		}
+		important, err := cmd.Flags().GetBool("important")
+		if err != nil {
+			log.Fatalf("Failed to get important flag: %v", err)
+		}
+		clipboardSelections, err := cmd.Flags().GetStringArray("clipboard-selection")
+		if err != nil {
+			log.Fatalf("Failed to get clipboard-selection flag: %v", err)
+		}
+		validateClipboardSelections(clipboardSelections)
+
+		in := cmd.InOrStdin()
+		if len(args) > 0 {
+			file, err := os.Open(args[0])
+			if err != nil {
+				log.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+			in = file
+		}
+
+		category := selections.SelectionRetentionTypeEphemeral
+		if important {
+			category = selections.SelectionRetentionTypeImportant
+		}
+		opts := []service.AddOption{
+			service.AddWithCategory(string(category)),
+			service.AddWithClipboardSelection(clipboardSelections),
+		}
+		if target != "" {
+			opts = append(opts, service.AddWithTarget(target))
+		}
+
+		resp, err := client.Add(ctx, in, opts...)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to add selection: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+		if err != nil {
+			log.Fatalf("Failed to print selection id: %v", err)
+		}
+	},
+}
+
+func init() {
+	addCmd.Flags().StringP("target", "t", "", "x11 target of the content, detected if not set")
+	addCmd.Flags().BoolP("important", "i", false, "store the content as important instead of ephemeral")
+	addCmd.Flags().StringArrayP("clipboard-selection", "c", []string{}, "also copy the content to the x11 clipboard selection [primary, secondary, clipboard]")
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"io"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Manage the selections dropped from the history",
+	Long: `Manage the selections dropped from the history
+Selections dropped by the retention limits and the policy are kept in a compressed archive
+with its own age and size limits. Find them with search --archive.`,
+}
+
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Store an archived selection back in the history",
+	Long: `Store an archived selection back in the history
+The selection goes back to the category it was dropped from unless --category is given.
+
+Example:
+blueclip client search --archive 'password reset'
+blueclip client archive restore 3f2a9c1b7d4e --category important`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		category, err := cmd.Flags().GetString("category")
+		if err != nil {
+			log.Fatalf("Failed to get category flag: %v", err)
+		}
+		opts := []service.RestoreOption{}
+		if category != "" {
+			opts = append(opts, service.RestoreWithCategory(category))
+		}
+
+		resp, err := client.RestoreArchived(ctx, args[0], opts...)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to restore selection: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+		if err != nil {
+			log.Fatalf("Failed to print selection id: %v", err)
+		}
+	},
+}
+
+func init() {
+	archiveRestoreCmd.Flags().String("category", "", "category to restore to [ephemeral, important, pinned]")
+	archiveCmd.AddCommand(archiveRestoreCmd)
+}
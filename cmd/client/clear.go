@@ -3,10 +3,10 @@ package client
 import (
 	"blueclip/pkg/service"
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +14,18 @@ import (
 var clearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear the clipboard",
+	Long: `Clear the clipboard
+Cleared selections are moved to the trash, bring them back with undo or trash restore.
+Use --permanent to delete them for good.
+
+The selector flags without --index clear every matching entry, the filters combine.
+Use --dry-run to print what would be cleared first.
+Pinned selections are kept unless --type, --category or --filter asks for the pinned category.
+
+Example:
+blueclip client clear --target image/png --larger-than 1MB --older-than 7d
+blueclip client clear --match '^https?://' --category ephemeral --dry-run
+blueclip client clear --filter 'target:image size:>1MB age:>7d'`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
@@ -29,11 +41,43 @@ var clearCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("Failed to get all flag: %v", err)
 		}
-		if clearAll {
-			cmd.SetIn(strings.NewReader(""))
+		permanent, err := cmd.Flags().GetBool("permanent")
+		if err != nil {
+			log.Fatalf("Failed to get permanent flag: %v", err)
 		}
 
-		resp, err := client.Clear(ctx, cmd.InOrStdin(), service.ClearWithType(clearType))
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			log.Fatalf("Failed to get dry-run flag: %v", err)
+		}
+
+		in := cmd.InOrStdin()
+		opts := []service.ClearOption{
+			service.ClearWithType(clearType),
+			service.ClearWithPermanent(permanent),
+		}
+		selector, ok := selectorFromFlags(cmd)
+		if ok {
+			in = nil
+			opts = append(opts, service.ClearWithSelector(selector))
+		}
+		// Without an index the selector clears every matching entry
+		if clearAll || (ok && selector.Index == nil) {
+			opts = append(opts, service.ClearWithAll(), service.ClearWithDryRun(dryRun))
+			result := service.ClearResult{}
+			resp, err := client.Clear(ctx, nil, opts...)
+			err = decodeResponse(resp, err, &result)
+			if err != nil {
+				log.Fatalf("Failed to clear clipboard: %v", err)
+			}
+			printClearResult(cmd.OutOrStdout(), result)
+			return
+		}
+		if dryRun {
+			log.Fatalf("--dry-run needs --all or filters without --index")
+		}
+
+		resp, err := client.Clear(ctx, in, opts...)
 		if err != nil {
 			log.Fatalf("Failed to clear clipboard: %v", err)
 		}
@@ -50,7 +94,22 @@ var clearCmd = &cobra.Command{
 	},
 }
 
+// printClearResult prints the cleared entries when it is a dry run and how many there are
+func printClearResult(out io.Writer, result service.ClearResult) {
+	if !result.DryRun {
+		fmt.Fprintf(out, "Cleared %d selections\n", result.Count)
+		return
+	}
+	for _, entry := range result.Entries {
+		fmt.Fprintf(out, "%s\t%s\n", entry.Category, entry.Line)
+	}
+	fmt.Fprintf(out, "%d selections would be cleared\n", result.Count)
+}
+
 func init() {
-	clearCmd.Flags().String("type", "all", "type of items to clear, [all, ephemeral, important]")
+	clearCmd.Flags().String("type", "all", "type of items to clear, [all, ephemeral, important, pinned], all keeps pinned items")
 	clearCmd.Flags().Bool("all", false, "clear all items, if not specified, it will read from stdin")
+	clearCmd.Flags().Bool("dry-run", false, "print the items that would be cleared by --all or the filters without clearing them")
+	clearCmd.Flags().Bool("permanent", false, "delete the items for good instead of moving them to the trash")
+	addSelectorFlags(clearCmd)
 }
@@ -31,6 +31,15 @@ or even replicate the copy to multiple clipboard selections
 Example:
 blueclip list | fzf | blueclip copy -c primary -c clipboard
 
+The selection can also be picked with --index, --category, --target and --match, see get.
+
+Example:
+blueclip copy --category important --index 0
+
+By default copied selections are moved to the important list,
+use --keep-category to only make them the most recent of their category.
+
+A line that matches no selection copies nothing and is not an error, so aborting fzf is safe.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
@@ -40,23 +49,19 @@ blueclip list | fzf | blueclip copy -c primary -c clipboard
 		if err != nil {
 			log.Fatalf("Failed to get clipboard-selection flag: %v", err)
 		}
-		for _, s := range clipboardSelections {
-			if xclip.ClipboardSelection(s) != xclip.ClipboardSelectionPrimary &&
-				xclip.ClipboardSelection(s) != xclip.ClipboardSelectionSecondary &&
-				xclip.ClipboardSelection(s) != xclip.ClipboardSelectionClipboard {
-				log.Fatalf("Invalid clipboard selection: %s, valid values are: %v", s, []string{
-					string(xclip.ClipboardSelectionPrimary),
-					string(xclip.ClipboardSelectionSecondary),
-					string(xclip.ClipboardSelectionClipboard),
-				})
-			}
-		}
+		validateClipboardSelections(clipboardSelections)
 
-		resp, err := client.Copy(
-			ctx,
-			cmd.InOrStdin(),
+		in := cmd.InOrStdin()
+		opts := []service.CopyOption{
 			service.CopyWithClipboardSelection(clipboardSelections),
-		)
+		}
+		opts = append(opts, keepCategoryOptions(cmd)...)
+		if selector, ok := selectorFromFlags(cmd); ok {
+			in = nil
+			opts = append(opts, service.CopyWithSelector(selector))
+		}
+
+		resp, err := client.Copy(ctx, in, opts...)
 		if err != nil {
 			log.Fatalf("Failed to copy selection: %v", err)
 		}
@@ -73,6 +78,40 @@ blueclip list | fzf | blueclip copy -c primary -c clipboard
 	},
 }
 
+// addKeepCategoryFlag adds the flag to choose whether copied selections are moved to the important list
+func addKeepCategoryFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("keep-category", false, "keep the selection in its category instead of moving it to important, defaults to the server configuration")
+}
+
+// keepCategoryOptions returns the copy options for the keep-category flag, none if it is not set
+func keepCategoryOptions(cmd *cobra.Command) []service.CopyOption {
+	if !cmd.Flags().Changed("keep-category") {
+		return nil
+	}
+	keep, err := cmd.Flags().GetBool("keep-category")
+	if err != nil {
+		log.Fatalf("Failed to get keep-category flag: %v", err)
+	}
+	return []service.CopyOption{service.CopyWithKeepCategory(keep)}
+}
+
+// validateClipboardSelections exits if any of the selections is not a valid x11 selection
+func validateClipboardSelections(clipboardSelections []string) {
+	for _, s := range clipboardSelections {
+		if xclip.ClipboardSelection(s) != xclip.ClipboardSelectionPrimary &&
+			xclip.ClipboardSelection(s) != xclip.ClipboardSelectionSecondary &&
+			xclip.ClipboardSelection(s) != xclip.ClipboardSelectionClipboard {
+			log.Fatalf("Invalid clipboard selection: %s, valid values are: %v", s, []string{
+				string(xclip.ClipboardSelectionPrimary),
+				string(xclip.ClipboardSelectionSecondary),
+				string(xclip.ClipboardSelectionClipboard),
+			})
+		}
+	}
+}
+
 func init() {
+	addSelectorFlags(copyCmd)
+	addKeepCategoryFlag(copyCmd)
 	copyCmd.Flags().StringArrayP("clipboard-selection", "c", []string{"clipboard"}, "x11 clipboard selection to copy to [primary, secondary, clipboard]")
 }
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var dmenuCmd = &cobra.Command{
+	Use:   "dmenu [-- launcher args...]",
+	Short: "Pick a selection with dmenu or any line based launcher",
+	Long: `Pick a selection with dmenu or any line based launcher
+Every entry is written as a single escaped line, line breaks become \n and
+backslashes become \\. The chosen line is mapped back to the entry and copied.
+
+Run the launcher and copy the chosen entry:
+blueclip client dmenu -- dmenu -i -l 20
+
+Or pipe the lines yourself and copy the chosen one:
+blueclip client dmenu | rofi -dmenu | blueclip client dmenu --copy -c primary -c clipboard`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		clipboardSelections, err := cmd.Flags().GetStringArray("clipboard-selection")
+		if err != nil {
+			log.Fatalf("Failed to get clipboard-selection flag: %v", err)
+		}
+		validateClipboardSelections(clipboardSelections)
+		copyChosen, err := cmd.Flags().GetBool("copy")
+		if err != nil {
+			log.Fatalf("Failed to get copy flag: %v", err)
+		}
+
+		if !copyChosen && len(args) == 0 {
+			err := writeDmenuLines(ctx, cmd.OutOrStdout(), client, orderOptions(cmd)...)
+			if err != nil {
+				log.Fatalf("Failed to list selections: %v", err)
+			}
+			return
+		}
+
+		var chosen []byte
+		if copyChosen {
+			chosen, err = io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				log.Fatalf("Failed to read chosen line: %v", err)
+			}
+		} else {
+			lines := &bytes.Buffer{}
+			err := writeDmenuLines(ctx, lines, client, orderOptions(cmd)...)
+			if err != nil {
+				log.Fatalf("Failed to list selections: %v", err)
+			}
+
+			launcherCmd := exec.CommandContext(ctx, args[0], args[1:]...)
+			launcherCmd.Stdin = lines
+			launcherCmd.Stderr = os.Stderr
+			chosen, err = launcherCmd.Output()
+			exitErr := &exec.ExitError{}
+			if errors.As(err, &exitErr) && len(bytes.TrimSpace(chosen)) == 0 {
+				// dmenu exits with an error when nothing is chosen
+				return
+			}
+			if err != nil {
+				log.Fatalf("Failed to run %s: %v", args[0], err)
+			}
+		}
+		if strings.TrimSpace(string(chosen)) == "" {
+			return
+		}
+
+		id, err := findEntryByLine(ctx, client, string(chosen))
+		if err != nil {
+			log.Fatalf("Failed to find selection: %v", err)
+		}
+		opts := []service.CopyOption{
+			service.CopyWithID(id),
+			service.CopyWithClipboardSelection(clipboardSelections),
+		}
+		resp, err := client.Copy(ctx, nil, append(opts, keepCategoryOptions(cmd)...)...)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to copy selection: %v", err)
+		}
+		resp.Body.Close()
+	},
+}
+
+func writeDmenuLines(ctx context.Context, out io.Writer, client *service.Client, opts ...service.ListOption) error {
+	entries, err := listEntries(ctx, client, opts...)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	for _, entry := range entries {
+		w.WriteString(launcherLine(entry))
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+func init() {
+	dmenuCmd.Flags().Bool("copy", false, "read the chosen line from stdin and copy it")
+	dmenuCmd.Flags().StringArrayP("clipboard-selection", "c", []string{"clipboard"}, "x11 clipboard selection to copy to [primary, secondary, clipboard]")
+	addKeepCategoryFlag(dmenuCmd)
+	addOrderFlag(dmenuCmd)
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"io"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print the selection picked by its position and filters",
+	Long: `Print the selection picked by its position and filters
+Entries are picked in list order, the index counts among the entries matching the filters.
+Without flags it prints the most recent selection.
+It fails if the index is out of range or if the filters match several entries and no index is given.
+
+Example:
+blueclip client get --category important --index 2
+blueclip client get --match '^https?://' --index 0
+blueclip client get --target image/png --index 0 > screenshot.png`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		selector, ok := selectorFromFlags(cmd)
+		if !ok {
+			index := 0
+			selector.Index = &index
+		}
+
+		resp, err := client.Print(ctx, nil, service.PrintWithSelector(selector))
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to get selection: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+		if err != nil {
+			log.Fatalf("Failed to print selection: %v", err)
+		}
+	},
+}
+
+func init() {
+	addSelectorFlags(getCmd)
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label [id] <label>",
+	Short: "Give a selection a label shown in the list and the pickers",
+	Long: `Give a selection a label shown in the list and the pickers
+Long queries and commands are hard to recognize from their first characters, a label
+is shown next to the content and search and the pickers match it too. An empty label removes it.
+The selection is picked by its id or with --index, --category, --target and --match, see get.
+
+Example:
+blueclip client label --match '^DELETE FROM' "prod db cleanup"
+blueclip client label 3f2a9c1b7d4e ""`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		label := args[len(args)-1]
+		selector, ok := selectorFromFlags(cmd)
+		var opt service.MoveOption
+		switch {
+		case len(args) > 1:
+			opt = service.MoveWithID(args[0])
+		case ok:
+			opt = service.MoveWithSelector(selector)
+		default:
+			log.Fatalf("Missing the id or a selector of the selection to label")
+		}
+
+		resp, err := client.Label(ctx, label, opt)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to label selection: %v", err)
+		}
+		resp.Body.Close()
+	},
+}
+
+func init() {
+	addSelectorFlags(labelCmd)
+}
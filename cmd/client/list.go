@@ -1,11 +1,13 @@
 package client
 
 import (
+	"blueclip/pkg/listformat"
 	"blueclip/pkg/service"
 	"context"
+	"fmt"
 	"io"
 	"log"
-	"net/http"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -14,13 +16,73 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List the clipboard history as single lines",
 	Long: `List the clipboard history as single lines
-Intended to be piped to other commands such as fzf, rofi, etc.`,
+Intended to be piped to other commands such as fzf, rofi, etc.
+
+By default every selection is written as is followed by a NUL character.
+Use --format with a preset or a Go template executed for every entry to get one row per entry.
+
+Presets: oneline, full, json
+
+Template fields:
+  .Index       position in the list, the last selection is 0
+  .ID          selection id
+  .Category    last, pinned, important or ephemeral
+  .Short       first letter of the category in upper case
+  .Target      x11 target such as UTF8_STRING or image/png
+  .Size        size in bytes
+  .Lines       number of lines
+  .Age         time since it was captured, such as 3m
+  .CapturedAt  time it was captured
+  .CopiedAt    last time it was copied, zero if never
+  .Label       label given with the label command or the command given to tee
+  .FirstLine   escaped first line, truncated
+  .Line        whole content escaped to a single line
+  .Content     raw content
+
+Template functions: escape, trunc <n>, bytes, lines
+
+Use --order to rank the entries after the last and pinned selections:
+  interleave  important and ephemeral interleaved from the most recent, the default
+  recent      last captured or copied first
+  frecency    often and recently captured or copied first, copies count more
+  size        largest first
+
+Selectors such as get --index use the same order when given the same --order.
+
+Example:
+blueclip client list --format oneline
+blueclip client list --format '{{.Short}} {{bytes .Size}} {{trunc 30 .Line}}'
+blueclip client list --format oneline --order frecency`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 
 		client := service.NewClient(socketPath)
-		resp, err := client.List(ctx)
+		format, err := cmd.Flags().GetString("format")
 		if err != nil {
+			log.Fatalf("Failed to get format flag: %v", err)
+		}
+		opts := orderOptions(cmd)
+		if f := filterFromFlags(cmd); f != "" {
+			opts = append(opts, service.ListWithFilter(f))
+		}
+		if format != "" {
+			formatter, err := listformat.New(format)
+			if err != nil {
+				log.Fatalf("Failed to parse format: %v", err)
+			}
+			entries, err := listEntries(ctx, client, opts...)
+			if err != nil {
+				log.Fatalf("Failed to list selections: %v", err)
+			}
+			err = formatter.Write(cmd.OutOrStdout(), entries, time.Now())
+			if err != nil {
+				log.Fatalf("Failed to print selections: %v", err)
+			}
+			return
+		}
+
+		resp, err := client.List(ctx, opts...)
+		if err := checkResponse(resp, err); err != nil {
 			log.Fatalf("Failed to list selections: %v", err)
 		}
 		defer resp.Body.Close()
@@ -29,9 +91,11 @@ Intended to be piped to other commands such as fzf, rofi, etc.`,
 		if err != nil {
 			log.Fatalf("Failed to print selection: %v", err)
 		}
-
-		if resp.StatusCode != http.StatusOK {
-			log.Fatalf("Failed to list selections: %v", resp.Status)
-		}
 	},
 }
+
+func init() {
+	addOrderFlag(listCmd)
+	addFilterFlag(listCmd)
+	listCmd.Flags().StringP("format", "f", "", fmt.Sprintf("format every entry with a preset %v or a Go template", listformat.PresetNames()))
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show or change the server log settings",
+	Long: `Show or change the server log settings
+Changes apply immediately and last until the server restarts.
+Clipboard content is never logged unless --content is enabled and the level is debug.
+
+Example:
+blueclip client log --level debug --content`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		opts := []service.LogOption{}
+		if cmd.Flags().Changed("level") {
+			level, err := cmd.Flags().GetString("level")
+			if err != nil {
+				log.Fatalf("Failed to get level flag: %v", err)
+			}
+			opts = append(opts, service.LogWithLevel(level))
+		}
+		if cmd.Flags().Changed("content") {
+			content, err := cmd.Flags().GetBool("content")
+			if err != nil {
+				log.Fatalf("Failed to get content flag: %v", err)
+			}
+			opts = append(opts, service.LogWithContent(content))
+		}
+
+		client := service.NewClient(socketPath)
+		resp, err := client.Log(ctx, opts...)
+		if err != nil {
+			log.Fatalf("Failed to get log settings: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			log.Fatalf("Failed to change log settings: %v: %s", resp.Status, body)
+		}
+
+		settings := service.LogSettings{}
+		err = json.NewDecoder(resp.Body).Decode(&settings)
+		if err != nil {
+			log.Fatalf("Failed to decode log settings: %v", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "level: %s\ncontent: %t\n", settings.Level, settings.Content)
+	},
+}
+
+func init() {
+	logCmd.Flags().String("level", "info", "log level [debug, info, warn, error]")
+	logCmd.Flags().Bool("content", false, "log clipboard content in debug messages")
+}
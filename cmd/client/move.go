@@ -0,0 +1,57 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promoteCmd = newMoveCmd("promote", "Move a selection to the important list", (*service.Client).Promote)
+	demoteCmd  = newMoveCmd("demote", "Move a selection to the ephemeral list", (*service.Client).Demote)
+	pinCmd     = newMoveCmd("pin", "Pin a selection so it is never evicted and listed first", (*service.Client).Pin)
+	unpinCmd   = newMoveCmd("unpin", "Move a pinned selection back to the important list", (*service.Client).Unpin)
+)
+
+type moveFunc func(c *service.Client, ctx context.Context, opts ...service.MoveOption) (*http.Response, error)
+
+func newMoveCmd(use string, short string, move moveFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " [id]",
+		Short: short,
+		Long: short + `
+The selection is picked by its id or with --index, --category, --target and --match, see get.
+Pinned selections must be unpinned before they can be promoted or demoted.
+
+Example:
+blueclip client ` + use + ` --index 0
+blueclip client list --format '{{.ID}} {{.FirstLine}}' | fzf | cut -d' ' -f1 | xargs blueclip client ` + use,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+
+			client := service.NewClient(socketPath)
+			selector, ok := selectorFromFlags(cmd)
+			var opt service.MoveOption
+			switch {
+			case len(args) > 0:
+				opt = service.MoveWithID(args[0])
+			case ok:
+				opt = service.MoveWithSelector(selector)
+			default:
+				log.Fatalf("Missing the id or a selector of the selection to %s", use)
+			}
+
+			resp, err := move(client, ctx, opt)
+			if err := checkResponse(resp, err); err != nil {
+				log.Fatalf("Failed to %s selection: %v", use, err)
+			}
+			resp.Body.Close()
+		},
+	}
+	addSelectorFlags(cmd)
+	return cmd
+}
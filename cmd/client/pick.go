@@ -0,0 +1,205 @@
+package client
+
+import (
+	"blueclip/pkg/listing"
+	"blueclip/pkg/picker"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"blueclip/pkg/xclip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Pick a selection with an interactive terminal UI",
+	Long: `Pick a selection with an interactive terminal UI
+Type to fuzzy filter the history, the preview pane shows the highlighted selection.
+
+Key bindings:
+  enter      copy to the clipboard and exit
+  ctrl-o     copy to the primary selection and exit
+  ctrl-s     promote to important
+  ctrl-d     delete
+  ctrl-e     show/hide ephemeral selections
+  ctrl-t     show/hide important selections
+  ctrl-f     show/hide pinned selections
+  ctrl-u     clear the filter
+  esc        exit`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		err := picker.Run(ctx, newClientBackend(cmd))
+		if err != nil {
+			log.Fatalf("Failed to run picker: %v", err)
+		}
+	},
+}
+
+func init() {
+	addKeepCategoryFlag(pickCmd)
+	addOrderFlag(pickCmd)
+}
+
+// clientBackend performs the picker actions through the server API
+type clientBackend struct {
+	client   *service.Client
+	copyOpts []service.CopyOption
+	listOpts []service.ListOption
+}
+
+// newClientBackend returns a backend for the copy and order flags of the command
+func newClientBackend(cmd *cobra.Command) *clientBackend {
+	return &clientBackend{
+		client:   service.NewClient(socketPath),
+		copyOpts: keepCategoryOptions(cmd),
+		listOpts: orderOptions(cmd),
+	}
+}
+
+func (b *clientBackend) Items(ctx context.Context) ([]picker.Item, error) {
+	entries, err := listEntries(ctx, b.client, b.listOpts...)
+	if err != nil {
+		return nil, err
+	}
+	registers, err := listRegisters(ctx, b.client)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]picker.Item, 0, len(entries)+len(registers))
+	for _, entry := range entries {
+		items = append(items, picker.Item{
+			ID:       entry.ID,
+			Category: entry.Category,
+			Target:   entry.Target,
+			Label:    entry.Label,
+			Line:     entry.Line,
+		})
+	}
+	// Registers are listed after the history, their IDs carry the register name
+	for _, register := range registers {
+		items = append(items, picker.Item{
+			ID:       registerPrefix + register.Name,
+			Target:   register.Target,
+			Register: register.Name,
+			Label:    register.Label,
+			Line:     register.Line,
+		})
+	}
+	return items, nil
+}
+
+// registerPrefix marks the picker IDs of the registers
+const registerPrefix = "register:"
+
+func (b *clientBackend) Preview(ctx context.Context, id string, width, height int) (string, error) {
+	pick := service.PrintWithID(id)
+	if name, ok := strings.CutPrefix(id, registerPrefix); ok {
+		pick = service.PrintWithRegister(name)
+	}
+	resp, err := b.client.Print(
+		ctx,
+		nil,
+		pick,
+		service.PrintWithUnindent(true),
+		service.PrintWithDimensions(width, height),
+	)
+	if err := checkResponse(resp, err); err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	preview, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read preview: %v", err)
+	}
+	return string(preview), nil
+}
+
+func (b *clientBackend) Copy(ctx context.Context, id string, clipboard xclip.ClipboardSelection) error {
+	pick := service.CopyWithID(id)
+	if name, ok := strings.CutPrefix(id, registerPrefix); ok {
+		pick = service.CopyWithRegister(name)
+	}
+	opts := []service.CopyOption{
+		pick,
+		service.CopyWithClipboardSelection([]string{string(clipboard)}),
+	}
+	resp, err := b.client.Copy(ctx, nil, append(opts, b.copyOpts...)...)
+	if err := checkResponse(resp, err); err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (b *clientBackend) Promote(ctx context.Context, id string) error {
+	if strings.HasPrefix(id, registerPrefix) {
+		return fmt.Errorf("registers can't be promoted")
+	}
+	resp, err := b.client.Promote(ctx, service.MoveWithID(id))
+	if err := checkResponse(resp, err); err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (b *clientBackend) Delete(ctx context.Context, id string) error {
+	if name, ok := strings.CutPrefix(id, registerPrefix); ok {
+		resp, err := b.client.ClearRegister(ctx, name)
+		if err := checkResponse(resp, err); err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	resp, err := b.client.Clear(
+		ctx,
+		nil,
+		service.ClearWithID(id),
+		service.ClearWithType(string(selections.SelectionRetentionTypeAll)),
+	)
+	if err := checkResponse(resp, err); err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// listEntries fetches the history in list order
+func listEntries(ctx context.Context, client *service.Client, opts ...service.ListOption) ([]listing.Entry, error) {
+	resp, err := client.List(ctx, append([]service.ListOption{service.ListWithFormat("json")}, opts...)...)
+	if err := checkResponse(resp, err); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	entries := []listing.Entry{}
+	err = json.NewDecoder(resp.Body).Decode(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode list: %v", err)
+	}
+	return entries, nil
+}
+
+// checkResponse turns failed requests and non OK responses into an error
+// carrying the message sent by the server
+func checkResponse(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = resp.Status
+		}
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientBackend_order(t *testing.T) {
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "blueclip.sock"))
+	require.NoError(t, err)
+	order := ""
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/list" {
+			order = req.URL.Query().Get("order")
+		}
+		resp.Write([]byte("[]"))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	previous := socketPath
+	socketPath = listener.Addr().String()
+	defer func() { socketPath = previous }()
+
+	require.NoError(t, pickCmd.Flags().Set("order", "size"))
+	defer pickCmd.Flags().Set("order", "")
+
+	_, err = newClientBackend(pickCmd).Items(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "size", order)
+}
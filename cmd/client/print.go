@@ -20,7 +20,10 @@ Intended to be a preview of the selection
 By default it prints the selection "as is", but it can be adjusted with flags.
 
 Example:
-blueclip list | fzf --preview-window right:wrap --preview 'echo {} | blueclip print' | blueclip copy`,
+blueclip list | fzf --preview-window right:wrap --preview 'echo {} | blueclip print' | blueclip copy
+
+The selection can also be picked with --index, --category, --target and --match, see get.
+A line that matches no selection prints nothing and is not an error.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
@@ -42,12 +45,17 @@ blueclip list | fzf --preview-window right:wrap --preview 'echo {} | blueclip pr
 			log.Fatalf("Failed to get height flag: %v", err)
 		}
 
-		resp, err := client.Print(
-			ctx,
-			cmd.InOrStdin(),
+		in := cmd.InOrStdin()
+		opts := []service.PrintOption{
 			service.PrintWithUnindent(unindent),
 			service.PrintWithDimensions(width, height),
-		)
+		}
+		if selector, ok := selectorFromFlags(cmd); ok {
+			in = nil
+			opts = append(opts, service.PrintWithSelector(selector))
+		}
+
+		resp, err := client.Print(ctx, in, opts...)
 		if err != nil {
 			log.Fatalf("Failed to print selection: %v", err)
 		}
@@ -83,4 +91,5 @@ func init() {
 
 	printCmd.Flags().Int("width", widthInt, "Width of the selection")
 	printCmd.Flags().Int("height", heightInt, "Height of the selection")
+	addSelectorFlags(printCmd)
 }
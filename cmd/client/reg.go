@@ -0,0 +1,169 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var regCmd = &cobra.Command{
+	Use:   "reg",
+	Short: "Manage the named registers a to z",
+	Long: `Manage the named registers a to z
+Registers hold one selection each, like vim registers. They are kept apart from the history,
+they are never evicted nor cleared, and the picker lists them after the history.`,
+}
+
+var regSetCmd = &cobra.Command{
+	Use:   "set <register>",
+	Short: "Store a selection in a register",
+	Long: `Store a selection in a register
+The content is read from stdin when it is not a terminal, otherwise the current selection is stored.
+Use --id or --index, --category, --target and --match to store a selection of the history, see get.
+
+Example:
+blueclip client reg set a
+git rev-parse HEAD | blueclip client reg set h
+blueclip client reg set s --match '^ssh '`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		id, err := cmd.Flags().GetString("id")
+		if err != nil {
+			log.Fatalf("Failed to get id flag: %v", err)
+		}
+		selector, ok := selectorFromFlags(cmd)
+		var in io.Reader
+		opts := []service.MoveOption{}
+		switch {
+		case id != "":
+			opts = append(opts, service.MoveWithID(id))
+		case ok:
+			opts = append(opts, service.MoveWithSelector(selector))
+		case !stdinIsTerminal(cmd):
+			in = cmd.InOrStdin()
+		}
+
+		register := service.RegisterEntry{}
+		resp, err := client.SetRegister(ctx, args[0], in, opts...)
+		if err := decodeResponse(resp, err, &register); err != nil {
+			log.Fatalf("Failed to set register: %v", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s  %s\n", register.Name, launcherLine(register.Entry))
+	},
+}
+
+var regGetCmd = &cobra.Command{
+	Use:   "get <register>",
+	Short: "Print the content of a register",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		resp, err := client.Print(ctx, nil, service.PrintWithRegister(args[0]))
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to get register: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+		if err != nil {
+			log.Fatalf("Failed to print register: %v", err)
+		}
+	},
+}
+
+var regCopyCmd = &cobra.Command{
+	Use:   "copy <register>",
+	Short: "Copy the content of a register to the clipboard",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		clipboardSelections, err := cmd.Flags().GetStringArray("clipboard-selection")
+		if err != nil {
+			log.Fatalf("Failed to get clipboard-selection flag: %v", err)
+		}
+		validateClipboardSelections(clipboardSelections)
+
+		resp, err := client.Copy(
+			ctx,
+			nil,
+			service.CopyWithRegister(args[0]),
+			service.CopyWithClipboardSelection(clipboardSelections),
+		)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to copy register: %v", err)
+		}
+		resp.Body.Close()
+	},
+}
+
+var regListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the registers holding a selection",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		registers, err := listRegisters(ctx, client)
+		if err != nil {
+			log.Fatalf("Failed to list registers: %v", err)
+		}
+		for _, register := range registers {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s  %s\n", register.Name, launcherLine(register.Entry))
+		}
+	},
+}
+
+var regRmCmd = &cobra.Command{
+	Use:   "rm <register>",
+	Short: "Empty a register",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		resp, err := client.ClearRegister(ctx, args[0])
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to empty register: %v", err)
+		}
+		resp.Body.Close()
+	},
+}
+
+// listRegisters fetches the registers holding a selection sorted by name
+func listRegisters(ctx context.Context, client *service.Client) ([]service.RegisterEntry, error) {
+	registers := []service.RegisterEntry{}
+	resp, err := client.Registers(ctx)
+	err = decodeResponse(resp, err, &registers)
+	return registers, err
+}
+
+// stdinIsTerminal reports whether the command input is an interactive terminal, which is never read
+func stdinIsTerminal(cmd *cobra.Command) bool {
+	file, ok := cmd.InOrStdin().(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+func init() {
+	regSetCmd.Flags().String("id", "", "id of the selection to store")
+	addSelectorFlags(regSetCmd)
+	regCopyCmd.Flags().StringArrayP("clipboard-selection", "c", []string{"clipboard"}, "x11 clipboard selection to copy to [primary, secondary, clipboard]")
+	regCmd.AddCommand(regSetCmd)
+	regCmd.AddCommand(regGetCmd)
+	regCmd.AddCommand(regCopyCmd)
+	regCmd.AddCommand(regListCmd)
+	regCmd.AddCommand(regRmCmd)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var revertCmd = &cobra.Command{
+	Use:   "revert",
+	Short: "Put back the previous content of the clipboard",
+	Long: `Put back the previous content of the clipboard
+blueclip remembers which selections owned each X selection. revert owns it again with the
+content and target it had before, without moving it in the history. Run it again to go further back.
+
+Example:
+blueclip client revert
+blueclip client revert --selection primary --steps 2`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		selection, err := cmd.Flags().GetString("selection")
+		if err != nil {
+			log.Fatalf("Failed to get selection flag: %v", err)
+		}
+		steps, err := cmd.Flags().GetInt("steps")
+		if err != nil {
+			log.Fatalf("Failed to get steps flag: %v", err)
+		}
+
+		resp, err := client.Revert(ctx, service.RevertWithSelection(selection), service.RevertWithSteps(steps))
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to revert clipboard: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+		if err != nil {
+			log.Fatalf("Failed to print selection: %v", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	revertCmd.Flags().String("selection", "clipboard", "X selection to revert [clipboard, primary]")
+	revertCmd.Flags().Int("steps", 1, "how many previous contents to go back")
+}
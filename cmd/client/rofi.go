@@ -0,0 +1,171 @@
+package client
+
+import (
+	"blueclip/pkg/launcher"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"blueclip/pkg/xclip"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// rofi custom keybindings, kb-custom-1 is alt+1 by default
+const (
+	rofiKeyDelete  = launcher.RofiCustomKey
+	rofiKeyPromote = launcher.RofiCustomKey + 1
+	rofiKeyPrimary = launcher.RofiCustomKey + 2
+)
+
+var rofiCmd = &cobra.Command{
+	Use:   "rofi",
+	Short: "Pick a selection with rofi in script mode",
+	Long: `Pick a selection with rofi in script mode
+rofi runs this command to list the history and again with the chosen entry.
+Use -show-icons to see an icon per target, important and pinned entries are highlighted as active.
+
+Example:
+rofi -modi "blueclip:blueclip client rofi" -show blueclip -show-icons
+rofi -modi "blueclip:blueclip client rofi --keep-category" -show blueclip
+rofi -modi "blueclip:blueclip client rofi --order frecency" -show blueclip
+
+Key bindings:
+  enter      copy to the clipboard
+  alt+1      delete (kb-custom-1)
+  alt+2      promote to important (kb-custom-2)
+  alt+3      copy to the primary selection (kb-custom-3)`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		retv := launcher.RofiInitial
+		if value := os.Getenv("ROFI_RETV"); value != "" {
+			var err error
+			retv, err = strconv.Atoi(value)
+			if err != nil {
+				log.Fatalf("Invalid ROFI_RETV %q: %v", value, err)
+			}
+		}
+
+		backend := newClientBackend(cmd)
+		if retv == launcher.RofiInitial || retv == launcher.RofiCustomInput {
+			err := writeRofiRows(ctx, cmd.OutOrStdout(), backend.client, backend.listOpts...)
+			if err != nil {
+				log.Fatalf("Failed to list selections: %v", err)
+			}
+			return
+		}
+
+		chosen := ""
+		if len(args) > 0 {
+			chosen = args[0]
+		}
+		id, err := rofiChosenID(ctx, backend.client, os.Getenv("ROFI_INFO"), chosen)
+		if err != nil {
+			log.Fatalf("Failed to find selection: %v", err)
+		}
+
+		switch retv {
+		case launcher.RofiSelected:
+			err = backend.Copy(ctx, id, xclip.ClipboardSelectionClipboard)
+		case rofiKeyPrimary:
+			err = backend.Copy(ctx, id, xclip.ClipboardSelectionPrimary)
+		case rofiKeyDelete:
+			err = backend.Delete(ctx, id)
+		case rofiKeyPromote:
+			err = backend.Promote(ctx, id)
+		default:
+			err = fmt.Errorf("unsupported key %d", retv)
+		}
+		if err != nil {
+			log.Fatalf("Failed to run action: %v", err)
+		}
+
+		// Printing nothing closes rofi, keep it open after managing entries
+		if retv == rofiKeyDelete || retv == rofiKeyPromote {
+			err := writeRofiRows(ctx, cmd.OutOrStdout(), backend.client, backend.listOpts...)
+			if err != nil {
+				log.Fatalf("Failed to list selections: %v", err)
+			}
+		}
+	},
+}
+
+func writeRofiRows(ctx context.Context, out io.Writer, client *service.Client, opts ...service.ListOption) error {
+	entries, err := listEntries(ctx, client, opts...)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+	launcher.WriteRofiOption(w, "prompt", "blueclip")
+	launcher.WriteRofiOption(w, "message", "alt+1 delete · alt+2 promote · alt+3 copy to primary")
+	launcher.WriteRofiOption(w, "use-hot-keys", "true")
+	launcher.WriteRofiOption(w, "no-custom", "true")
+	launcher.WriteRofiOption(w, "keep-selection", "true")
+
+	retained := []string{}
+	for i, entry := range entries {
+		if entry.Category == selections.SelectionRetentionTypeImportant || entry.Category == selections.SelectionRetentionTypePinned {
+			retained = append(retained, strconv.Itoa(i))
+		}
+	}
+	if len(retained) > 0 {
+		launcher.WriteRofiOption(w, "active", strings.Join(retained, ","))
+	}
+
+	for _, entry := range entries {
+		launcher.WriteRofiRow(w, launcher.RofiRow{
+			Text: launcherLine(entry),
+			Icon: launcher.Icon(entry.Target),
+			Info: entry.ID,
+		})
+	}
+	return w.Flush()
+}
+
+func init() {
+	addKeepCategoryFlag(rofiCmd)
+	addOrderFlag(rofiCmd)
+}
+
+// rofiChosenID prefers the info field, rofi versions without info support only pass the text
+func rofiChosenID(ctx context.Context, client *service.Client, info string, chosen string) (string, error) {
+	if info != "" {
+		return info, nil
+	}
+	return findEntryByLine(ctx, client, chosen)
+}
+
+// launcherLine is the single line shown for the entry in a launcher, prefixed by its label if any
+func launcherLine(entry listing.Entry) string {
+	if entry.Label == "" {
+		return launcher.Line(entry.Line)
+	}
+	return launcher.Line("[" + entry.Label + "] " + entry.Line)
+}
+
+// findEntryByLine maps a line chosen in a launcher back to the entry ID
+func findEntryByLine(ctx context.Context, client *service.Client, chosen string) (string, error) {
+	entries, err := listEntries(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = launcherLine(entry)
+	}
+	i, ok := launcher.Find(lines, chosen)
+	if !ok {
+		return "", fmt.Errorf("no selection matches %q", chosen)
+	}
+	return entries[i].ID, nil
+}
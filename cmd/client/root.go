@@ -23,6 +23,27 @@ func Register(base *cobra.Command) {
 	rootCmd.AddCommand(printCmd)
 	rootCmd.AddCommand(copyCmd)
 	rootCmd.AddCommand(clearCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(pickCmd)
+	rootCmd.AddCommand(rofiCmd)
+	rootCmd.AddCommand(dmenuCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(teeCmd)
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(promoteCmd)
+	rootCmd.AddCommand(demoteCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(revertCmd)
+	rootCmd.AddCommand(labelCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(collectionCmd)
+	rootCmd.AddCommand(regCmd)
 }
 
 func init() {
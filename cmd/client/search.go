@@ -0,0 +1,240 @@
+package client
+
+import (
+	"blueclip/pkg/listformat"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/search"
+	"blueclip/pkg/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	highlight      = "\x1b[1;31m"
+	resetHighlight = "\x1b[0m"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Print every entry matching the query and filters",
+	Long: `Print every entry matching the query and filters
+The query is matched by the server against the whole content, not just the first line,
+as a substring, a regular expression or a fuzzy pattern like the picker does.
+Substring and fuzzy queries are case sensitive only if they have upper case letters.
+The best matches come first, each one followed by its matching lines with the matches highlighted.
+The index of every entry is its position in the list with the same --order.
+
+Use --archive to search the selections dropped by the retention limits and the policy,
+the most recently archived first. Restore them with archive restore.
+
+Example:
+blueclip client search password
+blueclip client search --mode regex 'https?://'
+blueclip client search --mode fuzzy dkrun
+blueclip client search --target image/png
+blueclip client search --filter 'category:important age:<2d' token
+blueclip client search --archive 'password reset'
+blueclip client search --archive -f json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		archived, err := cmd.Flags().GetBool("archive")
+		if err != nil {
+			log.Fatalf("Failed to get archive flag: %v", err)
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			log.Fatalf("Failed to get format flag: %v", err)
+		}
+		var formatter *listformat.Formatter
+		if format != "" {
+			formatter, err = listformat.New(format)
+			if err != nil {
+				log.Fatalf("Failed to parse format: %v", err)
+			}
+		}
+		mode, err := cmd.Flags().GetString("mode")
+		if err != nil {
+			log.Fatalf("Failed to get mode flag: %v", err)
+		}
+		color, err := colorFromFlags(cmd)
+		if err != nil {
+			log.Fatalf("Failed to get color flag: %v", err)
+		}
+
+		selector := service.Selector{Order: orderFromFlags(cmd), Filter: filterFromFlags(cmd)}
+		for _, flag := range []struct {
+			name  string
+			value *string
+		}{
+			{name: "category", value: &selector.Category},
+			{name: "target", value: &selector.Target},
+		} {
+			*flag.value, err = cmd.Flags().GetString(flag.name)
+			if err != nil {
+				log.Fatalf("Failed to get %s flag: %v", flag.name, err)
+			}
+		}
+		opts := []service.SearchOption{service.SearchWithSelector(selector), service.SearchWithMode(mode)}
+		if len(args) > 0 {
+			opts = append(opts, service.SearchWithQuery(args[0]))
+		}
+
+		entries := []listing.Entry{}
+		matches := []service.SearchMatch{}
+		var results any
+		if archived {
+			records := []service.ArchiveEntry{}
+			resp, err := client.SearchArchive(ctx, opts...)
+			err = decodeResponse(resp, err, &records)
+			if err != nil {
+				log.Fatalf("Failed to search archive: %v", err)
+			}
+			for _, record := range records {
+				entries = append(entries, record.Entry)
+				matches = append(matches, record.SearchMatch)
+			}
+			results = records
+		} else {
+			found := []service.SearchResult{}
+			resp, err := client.Search(ctx, opts...)
+			err = decodeResponse(resp, err, &found)
+			if err != nil {
+				log.Fatalf("Failed to search selections: %v", err)
+			}
+			for _, result := range found {
+				entries = append(entries, result.Entry)
+				matches = append(matches, result.SearchMatch)
+			}
+			results = found
+		}
+
+		switch format {
+		case "":
+			err = writeMatches(cmd.OutOrStdout(), entries, matches, color)
+		case "json":
+			// Keep the search and archive fields
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			err = enc.Encode(results)
+		default:
+			err = formatter.Write(cmd.OutOrStdout(), entries, time.Now())
+		}
+		if err != nil {
+			log.Fatalf("Failed to print selections: %v", err)
+		}
+	},
+}
+
+// colorFromFlags reports whether to highlight the output, auto highlights on a terminal
+func colorFromFlags(cmd *cobra.Command) (bool, error) {
+	color, err := cmd.Flags().GetString("color")
+	if err != nil {
+		return false, err
+	}
+	switch color {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		out, ok := cmd.OutOrStdout().(*os.File)
+		return ok && term.IsTerminal(int(out.Fd())), nil
+	default:
+		return false, fmt.Errorf("invalid color %s, allowed values are: auto, always, never", color)
+	}
+}
+
+// writeMatches writes every entry with the full preset followed by its matching lines
+func writeMatches(out io.Writer, entries []listing.Entry, matches []service.SearchMatch, color bool) error {
+	header, err := listformat.New("full")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i, entry := range entries {
+		err := header.Write(out, []listing.Entry{entry}, now)
+		if err != nil {
+			return err
+		}
+		if len(matches[i].LabelOffsets) > 0 {
+			text, _ := highlightLine(entry.Label, 0, matches[i].LabelOffsets, color)
+			fmt.Fprintf(out, "    label: %s\n", text)
+		}
+		if len(matches[i].Offsets) == 0 {
+			continue
+		}
+
+		start := 0
+		for n, line := range strings.SplitAfter(entry.Line, "\n") {
+			text, ok := highlightLine(strings.TrimSuffix(line, "\n"), start, matches[i].Offsets, color)
+			start += len(line)
+			if ok {
+				fmt.Fprintf(out, "    %d: %s\n", n+1, text)
+			}
+		}
+	}
+	return nil
+}
+
+// highlightLine marks the parts of the line covered by the spans, which are offsets in the whole
+// content where the line begins at start. It reports whether any span covers the line.
+func highlightLine(line string, start int, spans []search.Span, color bool) (string, bool) {
+	out := &strings.Builder{}
+	found := false
+	written := 0
+	for _, span := range spans {
+		from := max(span[0]-start, written)
+		to := min(span[1]-start, len(line))
+		if from >= to {
+			continue
+		}
+		found = true
+		out.WriteString(line[written:from])
+		if color {
+			out.WriteString(highlight + line[from:to] + resetHighlight)
+		} else {
+			out.WriteString(line[from:to])
+		}
+		written = to
+	}
+	out.WriteString(line[written:])
+	return out.String(), found
+}
+
+// decodeResponse decodes the JSON body of a successful response into v
+func decodeResponse(resp *http.Response, err error, v any) error {
+	if err := checkResponse(resp, err); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	searchCmd.Flags().Bool("archive", false, "search the archived selections instead of the history")
+	searchCmd.Flags().String("mode", "", fmt.Sprintf("match the query with one of %v, defaults to substring", search.Modes))
+	searchCmd.Flags().String("color", "auto", "highlight the matches [auto, always, never]")
+	searchCmd.Flags().String("category", "", "only entries in the category [ephemeral, important, pinned]")
+	searchCmd.Flags().String("target", "", "only entries with the x11 target, such as image/png")
+	addOrderFlag(searchCmd)
+	addFilterFlag(searchCmd)
+	searchCmd.Flags().StringP("format", "f", "", fmt.Sprintf("format every entry with a preset %v or a Go template instead of printing the matching lines", listformat.PresetNames()))
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"blueclip/pkg/filter"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// addSelectorFlags adds the flags to pick an entry without piping its line
+func addSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("index", 0, "position among the matching entries, 0 is the most recent")
+	cmd.Flags().String("category", "", "only entries in the category [ephemeral, important, pinned]")
+	cmd.Flags().String("target", "", "only entries with the x11 target, such as image/png")
+	cmd.Flags().String("match", "", "only entries matching the regular expression")
+	cmd.Flags().String("older-than", "", "only entries last captured longer ago than the duration, such as 12h or 7d")
+	cmd.Flags().String("larger-than", "", "only entries with content larger than the size, such as 1MB")
+	addFilterFlag(cmd)
+	addOrderFlag(cmd)
+}
+
+// addFilterFlag adds the flag to filter the entries with a query
+func addFilterFlag(cmd *cobra.Command) {
+	cmd.Flags().String("filter", "", fmt.Sprintf("only entries matching the filter query, such as 'target:image age:<2h \"docker run\"', fields are %v", filter.Fields))
+}
+
+// filterFromFlags returns the filter flag, empty if it is not set
+func filterFromFlags(cmd *cobra.Command) string {
+	f, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		log.Fatalf("Failed to get filter flag: %v", err)
+	}
+	return f
+}
+
+// addOrderFlag adds the flag to rank the entries, list and index lookups with the same order agree
+func addOrderFlag(cmd *cobra.Command) {
+	cmd.Flags().String("order", "", fmt.Sprintf("rank the entries by one of %v, defaults to interleave", selections.Orders))
+}
+
+// orderFromFlags returns the order flag, empty if it is not set
+func orderFromFlags(cmd *cobra.Command) string {
+	order, err := cmd.Flags().GetString("order")
+	if err != nil {
+		log.Fatalf("Failed to get order flag: %v", err)
+	}
+	return order
+}
+
+// orderOptions returns the list options for the order flag, none if it is not set
+func orderOptions(cmd *cobra.Command) []service.ListOption {
+	order := orderFromFlags(cmd)
+	if order == "" {
+		return nil
+	}
+	return []service.ListOption{service.ListWithOrder(order)}
+}
+
+// selectorFromFlags returns the selector given by the flags, ok is false if none is set.
+// The order alone is not a selector, it only ranks the entries for the index.
+func selectorFromFlags(cmd *cobra.Command) (selector service.Selector, ok bool) {
+	selector.Order = orderFromFlags(cmd)
+	if cmd.Flags().Changed("index") {
+		index, err := cmd.Flags().GetInt("index")
+		if err != nil {
+			log.Fatalf("Failed to get index flag: %v", err)
+		}
+		selector.Index = &index
+		ok = true
+	}
+	for _, flag := range []struct {
+		name  string
+		value *string
+	}{
+		{name: "category", value: &selector.Category},
+		{name: "target", value: &selector.Target},
+		{name: "match", value: &selector.Match},
+		{name: "older-than", value: &selector.OlderThan},
+		{name: "larger-than", value: &selector.LargerThan},
+		{name: "filter", value: &selector.Filter},
+	} {
+		value, err := cmd.Flags().GetString(flag.name)
+		if err != nil {
+			log.Fatalf("Failed to get %s flag: %v", flag.name, err)
+		}
+		if value != "" {
+			*flag.value = value
+			ok = true
+		}
+	}
+	return selector, ok
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"blueclip/pkg/xclip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the server status and statistics",
+	Long: `Show the server status and statistics
+Reports the server version and uptime, the state of the clipboard watchers,
+the size of the history and the storage status.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		asJSON, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			log.Fatalf("Failed to get json flag: %v", err)
+		}
+
+		client := service.NewClient(socketPath)
+		resp, err := client.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get status: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("Failed to get status: %v", resp.Status)
+		}
+
+		if asJSON {
+			_, err = io.Copy(cmd.OutOrStdout(), resp.Body)
+			if err != nil {
+				log.Fatalf("Failed to print status: %v", err)
+			}
+			return
+		}
+
+		status := service.Status{}
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		if err != nil {
+			log.Fatalf("Failed to decode status: %v", err)
+		}
+		printStatus(cmd.OutOrStdout(), status)
+	},
+}
+
+func printStatus(out io.Writer, status service.Status) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Version:\t%s\n", status.Version)
+	fmt.Fprintf(w, "Uptime:\t%s\n", time.Duration(status.Uptime))
+
+	fmt.Fprintf(w, "\nWatchers:\n")
+	for _, watcher := range status.Watchers {
+		state := "stopped"
+		if watcher.Running {
+			state = "running"
+		}
+		fmt.Fprintf(w, "  %s\t%s\tlast poll %s\tlast change %s\n", watcher.Selection, state, formatAge(watcher.LastPoll), formatAge(watcher.LastChange))
+		if watcher.LastError != "" {
+			fmt.Fprintf(w, "\t\tlast error %s: %s\n", formatAge(watcher.LastErrorAt), watcher.LastError)
+		}
+	}
+
+	limits := status.Stats.Limits
+	fmt.Fprintf(w, "\nCategories:\n")
+	for _, category := range []struct {
+		typ      selections.SelectionRetentionType
+		maxCount int
+		maxBytes bytesize.Size
+	}{
+		{typ: selections.SelectionRetentionTypeEphemeral, maxCount: limits.MaxEphemeralElements, maxBytes: limits.Budget.Ephemeral},
+		{typ: selections.SelectionRetentionTypeImportant, maxCount: limits.MaxImportantElements, maxBytes: limits.Budget.Important},
+		{typ: selections.SelectionRetentionTypePinned},
+	} {
+		usage := status.Stats.Categories[category.typ]
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", category.typ, formatCount(usage.Count, category.maxCount), formatBudget(usage.Bytes, category.maxBytes))
+	}
+	fmt.Fprintf(w, "  total\t%d entries\t%s\n", status.Stats.Total.Count, formatBudget(status.Stats.Total.Bytes, limits.Budget.Total))
+
+	fmt.Fprintf(w, "\nTargets:\n")
+	targets := []string{}
+	for target := range status.Stats.Targets {
+		targets = append(targets, string(target))
+	}
+	slices.Sort(targets)
+	for _, target := range targets {
+		usage := status.Stats.Targets[xclip.ValidTarget(target)]
+		fmt.Fprintf(w, "  %s\t%d entries\t%s\n", target, usage.Count, formatBudget(usage.Bytes, limits.Budget.Targets[xclip.ValidTarget(target)]))
+	}
+
+	fmt.Fprintf(w, "\nStorage:\n")
+	fmt.Fprintf(w, "  path\t%s\n", status.Storage.Path)
+	fmt.Fprintf(w, "  last save\t%s\n", formatAge(status.Storage.LastSave))
+	if status.Storage.LastError != "" {
+		fmt.Fprintf(w, "  last error\t%s\n", status.Storage.LastError)
+	}
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
+
+// formatCount writes the number of entries and the limit if there is one
+func formatCount(count int, limit int) string {
+	if limit <= 0 {
+		return fmt.Sprintf("%d entries", count)
+	}
+	return fmt.Sprintf("%d/%d entries", count, limit)
+}
+
+// formatBudget writes the bytes used and the budget if there is one
+func formatBudget(b int, budget bytesize.Size) string {
+	if budget <= 0 {
+		return bytesize.Size(b).String()
+	}
+	return fmt.Sprintf("%s of %s", bytesize.Size(b), budget)
+}
+
+func init() {
+	statusCmd.Flags().Bool("json", false, "print the status as JSON")
+}
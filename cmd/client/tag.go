@@ -0,0 +1,106 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage the tags of the selections",
+	Long: `Manage the tags of the selections
+Tags are free-form names to find selections later, list them with --filter tag:<name>.`,
+}
+
+var collectionCmd = &cobra.Command{
+	Use:   "collection",
+	Short: "Manage the named collections of selections",
+	Long: `Manage the named collections of selections
+Selections in a collection are never evicted by the category limits, the budget or the expiry,
+list them with --filter collection:<name>.`,
+}
+
+type namesFunc func(c *service.Client, ctx context.Context, names []string, opts ...service.MoveOption) (*http.Response, error)
+
+func newNamesCmd(use string, short string, kind string, apply namesFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <" + kind + ">...",
+		Short: short,
+		Long: short + `
+The selection is picked by --id or with --index, --category, --target and --match, see get.
+Names can't have spaces or quotes.
+
+Example:
+blueclip client ` + kind + ` ` + use + ` --index 0 work`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+
+			client := service.NewClient(socketPath)
+			id, _ := cmd.Flags().GetString("id")
+			selector, ok := selectorFromFlags(cmd)
+			var opt service.MoveOption
+			switch {
+			case id != "":
+				opt = service.MoveWithID(id)
+			case ok:
+				opt = service.MoveWithSelector(selector)
+			default:
+				log.Fatalf("Missing the id or a selector of the selection")
+			}
+
+			resp, err := apply(client, ctx, args, opt)
+			if err := checkResponse(resp, err); err != nil {
+				log.Fatalf("Failed to %s %s: %v", use, kind, err)
+			}
+			resp.Body.Close()
+		},
+	}
+	cmd.Flags().String("id", "", "id of the selection")
+	addSelectorFlags(cmd)
+	return cmd
+}
+
+type countsFunc func(c *service.Client, ctx context.Context) (*http.Response, error)
+
+func newCountsCmd(short string, counts countsFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: short,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+
+			client := service.NewClient(socketPath)
+			found := map[string]int{}
+			resp, err := counts(client, ctx)
+			if err := decodeResponse(resp, err, &found); err != nil {
+				log.Fatalf("Failed to list: %v", err)
+			}
+
+			names := make([]string, 0, len(found))
+			for name := range found {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%d\n", name, found[name])
+			}
+		},
+	}
+}
+
+func init() {
+	tagCmd.AddCommand(newNamesCmd("add", "Add tags to a selection", "tag", (*service.Client).Tag))
+	tagCmd.AddCommand(newNamesCmd("rm", "Remove tags from a selection", "tag", (*service.Client).Untag))
+	tagCmd.AddCommand(newCountsCmd("Print the tags and how many selections have them", (*service.Client).Tags))
+	collectionCmd.AddCommand(newNamesCmd("add", "Add a selection to collections", "collection", (*service.Client).Collect))
+	collectionCmd.AddCommand(newNamesCmd("rm", "Remove a selection from collections", "collection", (*service.Client).Uncollect))
+	collectionCmd.AddCommand(newCountsCmd("Print the collections and how many selections they have", (*service.Client).Collections))
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var teeMaxSize = 10 * bytesize.MiB
+
+var teeCmd = &cobra.Command{
+	Use:   "tee",
+	Short: "Copy stdin to stdout and record it in the history",
+	Long: `Copy stdin to stdout and record it in the history
+The output is streamed to the server, content over --max-size is dropped by the server
+so the output is never buffered. Failing to record the output doesn't interrupt it,
+a warning is written to stderr instead.
+
+The entry is labelled with --label or with the command line writing to stdin.
+
+Example:
+make test 2>&1 | blueclip client tee
+kubectl get pods | blueclip client tee --label pods -c clipboard`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			log.Fatalf("Failed to get label flag: %v", err)
+		}
+		target, err := cmd.Flags().GetString("target")
+		if err != nil {
+			log.Fatalf("Failed to get target flag: %v", err)
+		}
+		important, err := cmd.Flags().GetBool("important")
+		if err != nil {
+			log.Fatalf("Failed to get important flag: %v", err)
+		}
+		clipboardSelections, err := cmd.Flags().GetStringArray("clipboard-selection")
+		if err != nil {
+			log.Fatalf("Failed to get clipboard-selection flag: %v", err)
+		}
+		validateClipboardSelections(clipboardSelections)
+
+		if label == "" {
+			label = producerCommand()
+		}
+		category := selections.SelectionRetentionTypeEphemeral
+		if important {
+			category = selections.SelectionRetentionTypeImportant
+		}
+		opts := []service.AddOption{
+			service.AddWithCategory(string(category)),
+			service.AddWithClipboardSelection(clipboardSelections),
+			service.AddWithLabel(label),
+			service.AddWithMaxSize(int64(teeMaxSize)),
+		}
+		if target != "" {
+			opts = append(opts, service.AddWithTarget(target))
+		}
+
+		body, recorder := io.Pipe()
+		recorded := make(chan error, 1)
+		truncated := false
+		go func() {
+			resp, err := client.Add(ctx, body, opts...)
+			err = checkResponse(resp, err)
+			if err == nil {
+				truncated = resp.Header.Get(service.TruncatedHeader) == "true"
+				resp.Body.Close()
+			}
+			// Unblock the writer if the server stopped reading
+			body.CloseWithError(io.ErrClosedPipe)
+			recorded <- err
+		}()
+
+		w := &bestEffortWriter{w: recorder}
+		_, err = io.Copy(cmd.OutOrStdout(), io.TeeReader(cmd.InOrStdin(), w))
+		recorder.Close()
+		if err != nil {
+			log.Fatalf("Failed to copy stdin: %v", err)
+		}
+
+		err = <-recorded
+		switch {
+		case w.written == 0:
+		case err != nil:
+			log.Printf("Warning: output not recorded: %v", err)
+		case truncated:
+			log.Printf("Warning: output truncated to %s", teeMaxSize)
+		}
+	},
+}
+
+// bestEffortWriter ignores errors so the pass through goes on when recording fails
+type bestEffortWriter struct {
+	w       io.Writer
+	written int64
+	failed  bool
+}
+
+func (b *bestEffortWriter) Write(p []byte) (int, error) {
+	b.written += int64(len(p))
+	if !b.failed {
+		_, err := b.w.Write(p)
+		b.failed = err != nil
+	}
+	return len(p), nil
+}
+
+// producerCommand returns the command line of the process writing to stdin,
+// or of the parent process if stdin is not a pipe or the writer can't be found
+func producerCommand() string {
+	stdin, err := os.Readlink("/proc/self/fd/0")
+	if err == nil && strings.HasPrefix(stdin, "pipe:") {
+		self := strconv.Itoa(os.Getpid())
+		stdouts, _ := filepath.Glob("/proc/[0-9]*/fd/1")
+		for _, stdout := range stdouts {
+			pid := filepath.Base(filepath.Dir(filepath.Dir(stdout)))
+			if pid == self {
+				continue
+			}
+			link, err := os.Readlink(stdout)
+			if err == nil && link == stdin {
+				return commandLine(pid)
+			}
+		}
+	}
+	return commandLine(strconv.Itoa(os.Getppid()))
+}
+
+func commandLine(pid string) string {
+	cmdline, err := os.ReadFile(filepath.Join("/proc", pid, "cmdline"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(strings.Split(string(cmdline), "\000"), " "))
+}
+
+func init() {
+	teeCmd.Flags().StringP("label", "l", "", "label of the entry, defaults to the command line writing to stdin")
+	teeCmd.Flags().StringP("target", "t", "", "x11 target of the content, detected if not set")
+	teeCmd.Flags().BoolP("important", "i", false, "store the content as important instead of ephemeral")
+	teeCmd.Flags().StringArrayP("clipboard-selection", "c", []string{}, "also copy the content to the x11 clipboard selection [primary, secondary, clipboard]")
+	teeCmd.Flags().Var(&teeMaxSize, "max-size", "largest content recorded, such as 512K or 10M")
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"blueclip/pkg/service"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore the selections removed by the last clear",
+	Long: `Restore the selections removed by the last clear
+Every clear moves the selections it removes to the trash as a single batch,
+undo puts the most recent batch back where it was. Run it again to undo the previous clear.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		resp, err := client.Undo(ctx)
+		restored(cmd, resp, err)
+	},
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage the cleared selections",
+	Long: `Manage the cleared selections
+Cleared selections are kept in the trash for the retention set in the trash config, 7 days by default.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the batches in the trash, the most recent first",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		batches := []service.TrashBatchEntry{}
+		resp, err := client.Trash(ctx)
+		err = decodeResponse(resp, err, &batches)
+		if err != nil {
+			log.Fatalf("Failed to list trash: %v", err)
+		}
+
+		out := cmd.OutOrStdout()
+		for _, batch := range batches {
+			fmt.Fprintf(out, "%d\t%s\t%d selections\n", batch.ID, batch.ClearedAt.Format(time.DateTime), len(batch.Entries))
+			for _, entry := range batch.Entries {
+				fmt.Fprintf(out, "\t%s\t%s\n", entry.Category, entry.Line)
+			}
+		}
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <batch>",
+	Short: "Restore the selections removed by a clear",
+	Long: `Restore the selections removed by a clear
+The batch is the number printed by trash list.
+
+Example:
+blueclip client trash list
+blueclip client trash restore 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		batch, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Fatalf("Invalid batch %q: %v", args[0], err)
+		}
+
+		client := service.NewClient(socketPath)
+		resp, err := client.RestoreTrash(ctx, batch)
+		restored(cmd, resp, err)
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Delete every selection in the trash for good",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		client := service.NewClient(socketPath)
+		resp, err := client.EmptyTrash(ctx)
+		if err := checkResponse(resp, err); err != nil {
+			log.Fatalf("Failed to empty trash: %v", err)
+		}
+		resp.Body.Close()
+	},
+}
+
+// restored prints how many selections a trash batch put back
+func restored(cmd *cobra.Command, resp *http.Response, err error) {
+	batch := service.TrashBatchEntry{}
+	err = decodeResponse(resp, err, &batch)
+	if err != nil {
+		log.Fatalf("Failed to restore selections: %v", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored %d selections cleared at %s\n", len(batch.Entries), batch.ClearedAt.Format(time.DateTime))
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"blueclip/pkg/config"
+	"blueclip/pkg/db"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/service"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type checkStatus string
+
+const (
+	checkStatusPass checkStatus = "PASS"
+	checkStatusWarn checkStatus = "WARN"
+	checkStatusFail checkStatus = "FAIL"
+)
+
+type checkResult struct {
+	status  checkStatus
+	message string
+	hint    string
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context) checkResult
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common setup problems",
+	Long: `Diagnose common setup problems
+Checks that xclip is installed and can reach the X server, that the server
+socket is reachable and that the history and configuration files are valid.
+
+Exits with a non zero status if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		socketPath, err := cmd.Flags().GetString("socket")
+		if err != nil {
+			log.Fatalf("Failed to get socket flag: %v", err)
+		}
+		historyPath, err := cmd.Flags().GetString("history")
+		if err != nil {
+			log.Fatalf("Failed to get history flag: %v", err)
+		}
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			log.Fatalf("Failed to get config flag: %v", err)
+		}
+
+		checks := []check{
+			{name: "xclip", run: checkXClip},
+			{name: "display", run: checkDisplay},
+			{name: "clipboard selection", run: checkTargets(xclip.ClipboardSelectionClipboard)},
+			{name: "primary selection", run: checkTargets(xclip.ClipboardSelectionPrimary)},
+			{name: "socket", run: checkSocket(socketPath)},
+			{name: "history", run: checkHistory(historyPath)},
+			{name: "config", run: checkConfig(configPath)},
+		}
+
+		out := cmd.OutOrStdout()
+		failed := false
+		for _, c := range checks {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			result := c.run(ctx)
+			cancel()
+
+			fmt.Fprintf(out, "[%s] %s: %s\n", result.status, c.name, result.message)
+			if result.hint != "" {
+				fmt.Fprintf(out, "       hint: %s\n", result.hint)
+			}
+			if result.status == checkStatusFail {
+				failed = true
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func checkXClip(ctx context.Context) checkResult {
+	path, err := exec.LookPath("xclip")
+	if err != nil {
+		return checkResult{
+			status:  checkStatusFail,
+			message: "xclip not found in PATH",
+			hint:    "install xclip with your package manager, for example: sudo apt install xclip",
+		}
+	}
+
+	// xclip prints the version to stderr
+	var output bytes.Buffer
+	version := exec.CommandContext(ctx, path, "-version")
+	version.Stdout = &output
+	version.Stderr = &output
+	if err := version.Run(); err != nil {
+		return checkResult{
+			status:  checkStatusFail,
+			message: fmt.Sprintf("failed to run %s -version: %v", path, err),
+			hint:    "reinstall xclip",
+		}
+	}
+	firstLine, _, _ := strings.Cut(output.String(), "\n")
+	return checkResult{
+		status:  checkStatusPass,
+		message: fmt.Sprintf("%s (%s)", strings.TrimSpace(firstLine), path),
+	}
+}
+
+func checkDisplay(ctx context.Context) checkResult {
+	display := os.Getenv("DISPLAY")
+	if display == "" {
+		return checkResult{
+			status:  checkStatusFail,
+			message: "DISPLAY is not set",
+			hint:    "when running under systemd, run `systemctl --user import-environment DISPLAY XAUTHORITY` from your session startup or add Environment=DISPLAY=:0 to the unit",
+		}
+	}
+	return checkResult{
+		status:  checkStatusPass,
+		message: "DISPLAY=" + display,
+	}
+}
+
+func checkTargets(selection xclip.ClipboardSelection) func(ctx context.Context) checkResult {
+	return func(ctx context.Context) checkResult {
+		targets, err := xclip.Cli.Targets(ctx, xclip.TargetsOptionWithSelection(selection))
+		if err != nil {
+			return checkResult{
+				status:  checkStatusWarn,
+				message: fmt.Sprintf("failed to query TARGETS: %v", strings.TrimSpace(err.Error())),
+				hint:    "this is expected if nothing was selected yet, otherwise check that DISPLAY points to your X server and XAUTHORITY is readable",
+			}
+		}
+		available := []string{}
+		for _, t := range targets {
+			if t != xclip.ValidTargetUnknown {
+				available = append(available, string(t))
+			}
+		}
+		return checkResult{
+			status:  checkStatusPass,
+			message: fmt.Sprintf("%d targets available", len(available)),
+		}
+	}
+}
+
+func checkSocket(socketPath string) func(ctx context.Context) checkResult {
+	return func(ctx context.Context) checkResult {
+		_, err := os.Stat(socketPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return checkResult{
+					status:  checkStatusFail,
+					message: fmt.Sprintf("socket %s does not exist", socketPath),
+					hint:    "start the server with `blueclip server`. If it runs as a systemd unit with PrivateTmp=yes the socket is hidden in a private /tmp, disable PrivateTmp or point --socket to the private path",
+				}
+			}
+			return checkResult{
+				status:  checkStatusFail,
+				message: fmt.Sprintf("failed to stat socket: %v", err),
+			}
+		}
+
+		d := net.Dialer{}
+		conn, err := d.DialContext(ctx, "unix", socketPath)
+		if err != nil {
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				return checkResult{
+					status:  checkStatusFail,
+					message: fmt.Sprintf("socket %s exists but nobody is listening", socketPath),
+					hint:    fmt.Sprintf("the socket is stale, remove it with `rm %s` and restart the server", socketPath),
+				}
+			}
+			return checkResult{
+				status:  checkStatusFail,
+				message: fmt.Sprintf("failed to connect to %s: %v", socketPath, err),
+				hint:    "make sure the socket belongs to your user",
+			}
+		}
+		conn.Close()
+
+		client := service.NewClient(socketPath)
+		resp, err := client.Status(ctx)
+		if err != nil {
+			return checkResult{
+				status:  checkStatusFail,
+				message: fmt.Sprintf("failed to reach the server: %v", err),
+				hint:    "check the server logs, for example with `journalctl --user -u blueclip`",
+			}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return checkResult{
+				status:  checkStatusWarn,
+				message: fmt.Sprintf("server replied %s: %s", resp.Status, body),
+				hint:    "the server may be older than the client, restart it after upgrading",
+			}
+		}
+
+		status := service.Status{}
+		if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+			return checkResult{
+				status:  checkStatusWarn,
+				message: fmt.Sprintf("failed to decode server status: %v", err),
+			}
+		}
+		for _, w := range status.Watchers {
+			if !w.Running {
+				return checkResult{
+					status:  checkStatusFail,
+					message: fmt.Sprintf("server %s is reachable but the %s watcher is stopped", status.Version, w.Selection),
+					hint:    "restart the server and check its logs",
+				}
+			}
+		}
+		return checkResult{
+			status:  checkStatusPass,
+			message: fmt.Sprintf("server %s reachable, up %s", status.Version, time.Duration(status.Uptime)),
+		}
+	}
+}
+
+func checkHistory(historyPath string) func(ctx context.Context) checkResult {
+	return func(ctx context.Context) checkResult {
+		fileDB, err := db.NewFileDB(historyPath)
+		if err != nil {
+			return checkResult{
+				status:  checkStatusFail,
+				message: err.Error(),
+			}
+		}
+
+		info, err := os.Stat(fileDB.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return checkResult{
+					status:  checkStatusPass,
+					message: fmt.Sprintf("%s does not exist yet, it will be created on the first capture", fileDB.Path),
+				}
+			}
+			return checkResult{
+				status:  checkStatusFail,
+				message: fmt.Sprintf("failed to stat %s: %v", fileDB.Path, err),
+				hint:    fmt.Sprintf("make sure %s is readable by your user", filepath.Dir(fileDB.Path)),
+			}
+		}
+
+		set := selections.NewSelections()
+		if err := fileDB.Load(set); err != nil {
+			return checkResult{
+				status:  checkStatusFail,
+				message: fmt.Sprintf("failed to read %s: %v", fileDB.Path, err),
+				hint:    fmt.Sprintf("the history is corrupted or unreadable, move it away with `mv %s %s.bak` and restart the server", fileDB.Path, fileDB.Path),
+			}
+		}
+		stats := set.Stats()
+		return checkResult{
+			status: checkStatusPass,
+			message: fmt.Sprintf("%s (%d bytes) holds %d ephemeral, %d important and %d pinned entries",
+				fileDB.Path,
+				info.Size(),
+				stats.Categories[selections.SelectionRetentionTypeEphemeral].Count,
+				stats.Categories[selections.SelectionRetentionTypeImportant].Count,
+				stats.Categories[selections.SelectionRetentionTypePinned].Count,
+			),
+		}
+	}
+}
+
+func checkConfig(configPath string) func(ctx context.Context) checkResult {
+	return func(ctx context.Context) checkResult {
+		_, err := config.Load(configPath)
+		if err != nil {
+			return checkResult{
+				status:  checkStatusFail,
+				message: err.Error(),
+				hint:    "fix the configuration file, see the Configuration section of the README",
+			}
+		}
+		return checkResult{
+			status:  checkStatusPass,
+			message: fmt.Sprintf("%s is valid", configPath),
+		}
+	}
+}
+
+func init() {
+	doctorCmd.Flags().StringP("socket", "s", filepath.Join(os.TempDir(), "blueclip", "blueclip.sock"), "path to the unix socket")
+	doctorCmd.Flags().StringP("history", "p", "~/.cache/blueclip/history.bin", "path to the history file")
+	doctorCmd.Flags().StringP("config", "c", "~/.config/blueclip.yaml", "path to the config file")
+}
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"blueclip/cmd/client"
+	"blueclip/pkg/service"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -33,6 +34,8 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.Version = service.Version
 	rootCmd.AddCommand(serverCmd)
+	rootCmd.AddCommand(doctorCmd)
 	client.Register(rootCmd)
 }
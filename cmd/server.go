@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"blueclip/pkg/config"
 	"blueclip/pkg/db"
+	"blueclip/pkg/logging"
 	"blueclip/pkg/service"
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,7 +20,45 @@ var serverCmd = &cobra.Command{
 	Short: "Start the server",
 	Long:  `Start the server`,
 	Run: func(cmd *cobra.Command, args []string) {
-		log.Println("Watching clipboard...")
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			fatal("Failed to get path to the config file", "error", err)
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			fatal("Failed to load config", "error", err)
+		}
+
+		if cmd.Flags().Changed("metrics-listen") {
+			cfg.Metrics.Listen, err = cmd.Flags().GetString("metrics-listen")
+			if err != nil {
+				fatal("Failed to get metrics-listen flag", "error", err)
+			}
+			if err := cfg.Metrics.Validate(); err != nil {
+				fatal("Invalid metrics-listen flag", "error", err)
+			}
+		}
+		if cmd.Flags().Changed("log-level") {
+			cfg.Logging.Level, err = cmd.Flags().GetString("log-level")
+			if err != nil {
+				fatal("Failed to get log-level flag", "error", err)
+			}
+		}
+		if cmd.Flags().Changed("log-format") {
+			format, err := cmd.Flags().GetString("log-format")
+			if err != nil {
+				fatal("Failed to get log-format flag", "error", err)
+			}
+			cfg.Logging.Format = logging.Format(format)
+		}
+
+		err = logging.Setup(os.Stderr, cfg.Logging)
+		if err != nil {
+			fatal("Failed to set up logging", "error", err)
+		}
+
+		slog.Info("Watching clipboard")
 		// Set up signal handling for graceful shutdown
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -30,35 +70,46 @@ var serverCmd = &cobra.Command{
 		go func() {
 			<-sigChan
 			cancel()
-			log.Println("Shutting down...")
+			slog.Info("Shutting down")
 			<-sigChan
-			log.Println("Force shutting down...")
+			slog.Warn("Force shutting down")
 			os.Exit(1)
 		}()
 
 		path, err := cmd.Flags().GetString("history")
 		if err != nil {
-			log.Fatalf("Failed to get path to the history file: %v", err)
+			fatal("Failed to get path to the history file", "error", err)
 		}
 
 		db, err := db.NewFileDB(path)
 		if err != nil {
-			log.Fatalf("Failed to create db: %v", err)
+			fatal("Failed to create db", "error", err)
 		}
 
-		service := service.NewService(db)
+		service, err := service.NewService(db, cfg)
+		if err != nil {
+			fatal("Failed to create service", "error", err)
+		}
 		err = service.Run(ctx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				log.Println("Context canceled, exiting")
+				slog.Info("Context canceled, exiting")
 				return
 			}
-			log.Fatalf("Failed to run service: %v", err)
+			fatal("Failed to run service", "error", err)
 		}
 	},
 }
 
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
 func init() {
 	serverCmd.Flags().StringP("history", "p", "~/.cache/blueclip/history.bin", "path to the history file")
 	serverCmd.Flags().StringP("config", "c", "~/.config/blueclip.yaml", "path to the config file")
+	serverCmd.Flags().String("metrics-listen", "", "localhost address to expose prometheus metrics, for example 127.0.0.1:9464")
+	serverCmd.Flags().String("log-level", "info", "log level [debug, info, warn, error]")
+	serverCmd.Flags().String("log-format", "text", "log format [text, json]")
 }
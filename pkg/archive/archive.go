@@ -0,0 +1,230 @@
+// Package archive keeps the selections dropped from the history in a compressed append-only file
+package archive
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/selections"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is a selection dropped from the history
+type Record struct {
+	selections.Selection
+	// Category is where the selection was stored when it was dropped
+	Category   selections.SelectionRetentionType
+	ArchivedAt time.Time
+	Reason     string
+}
+
+// Options limits what the archive keeps, zero values are unlimited
+type Options struct {
+	MaxAge  time.Duration
+	MaxSize bytesize.Size
+}
+
+// Archive is a file of gzip members, every append adds a member with one JSON record per line.
+// Records are only removed when the archive is compacted.
+type Archive struct {
+	Path    string
+	Options Options
+
+	lock sync.Mutex
+}
+
+func New(path string, options Options) (*Archive, error) {
+	// Resolve ~ to user's home directory
+	if len(path) > 1 && path[:2] == "~/" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %v", err)
+		}
+		path = home + path[1:]
+	}
+	return &Archive{
+		Path:    path,
+		Options: options,
+	}, nil
+}
+
+// Append adds the records at the end of the archive
+func (a *Archive) Append(records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	os.MkdirAll(filepath.Dir(a.Path), 0755)
+	f, err := os.OpenFile(a.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	err = write(f, records)
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %v", err)
+	}
+	return f.Close()
+}
+
+func write(w io.Writer, records []Record) error {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Records returns every record in the order they were archived
+func (a *Archive) Records() ([]Record, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.read()
+}
+
+// read loads the records, the caller must hold the lock. A partially written member at the end,
+// left by a crash, is ignored.
+func (a *Archive) read() ([]Record, error) {
+	records := []Record{}
+
+	f, err := os.Open(a.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+	dec := json.NewDecoder(zr)
+	for {
+		record := Record{}
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			slog.Warn("Archive ends with an incomplete record, ignoring it", "path", a.Path)
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode archive: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// Search returns the matching records from the most recently archived.
+// A selection archived more than once is only returned once.
+func (a *Archive) Search(match func(Record) bool) ([]Record, error) {
+	records, err := a.Records()
+	if err != nil {
+		return nil, err
+	}
+	found := []Record{}
+	for _, record := range latest(records) {
+		if match(record) {
+			found = append(found, record)
+		}
+	}
+	return found, nil
+}
+
+// Find returns the most recent record of the selection with the given ID
+func (a *Archive) Find(id string) (Record, bool, error) {
+	found, err := a.Search(func(record Record) bool {
+		return record.ID() == id
+	})
+	if err != nil || len(found) == 0 {
+		return Record{}, false, err
+	}
+	return found[0], true, nil
+}
+
+// latest returns the last record of every selection, the most recently archived first
+func latest(records []Record) []Record {
+	seen := map[string]bool{}
+	result := []Record{}
+	for _, record := range slices.Backward(records) {
+		id := record.ID()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, record)
+	}
+	return result
+}
+
+// Compact drops duplicated records and the records over the limits, the oldest first.
+// The archive is only rewritten if something is dropped. It returns the number of records dropped.
+func (a *Archive) Compact(now time.Time) (int, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	records, err := a.read()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := []Record{}
+	size := bytesize.Size(0)
+	for _, record := range latest(records) {
+		if a.Options.MaxAge > 0 && now.Sub(record.ArchivedAt) > a.Options.MaxAge {
+			continue
+		}
+		size += bytesize.Size(len(record.Content))
+		if a.Options.MaxSize > 0 && size > a.Options.MaxSize {
+			continue
+		}
+		kept = append(kept, record)
+	}
+	dropped := len(records) - len(kept)
+	if dropped == 0 {
+		return 0, nil
+	}
+	slices.Reverse(kept)
+
+	tmp := a.Path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create archive: %v", err)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	err = write(f, kept)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write archive: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %v", err)
+	}
+	if err := os.Rename(tmp, a.Path); err != nil {
+		return 0, fmt.Errorf("failed to replace archive: %v", err)
+	}
+	return dropped, nil
+}
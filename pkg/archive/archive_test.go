@@ -0,0 +1,101 @@
+package archive
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(content string, archivedAt time.Time) Record {
+	return Record{
+		Selection: selections.Selection{
+			Selection: xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard),
+			Label:     "test",
+		},
+		Category:   selections.SelectionRetentionTypeEphemeral,
+		ArchivedAt: archivedAt,
+		Reason:     "ephemeral limit",
+	}
+}
+
+func contents(records []Record) []string {
+	result := []string{}
+	for _, r := range records {
+		result = append(result, string(r.Content))
+	}
+	return result
+}
+
+func TestArchive(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, err := New(filepath.Join(t.TempDir(), "history.archive"), Options{})
+	require.NoError(t, err)
+
+	records, err := a.Records()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, a.Append([]Record{record("one", start), record("two", start.Add(time.Hour))}))
+	require.NoError(t, a.Append([]Record{record("one", start.Add(2*time.Hour)), record("three", start.Add(3*time.Hour))}))
+
+	records, err = a.Records()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "one", "three"}, contents(records))
+	assert.Equal(t, "test", records[0].Label)
+
+	found, err := a.Search(func(r Record) bool { return strings.Contains(string(r.Content), "o") })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, contents(found))
+	assert.Equal(t, start.Add(2*time.Hour), found[0].ArchivedAt)
+
+	r, ok, err := a.Find(found[1].ID())
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "two", string(r.Content))
+
+	// A crash while appending leaves an incomplete member
+	f, err := os.OpenFile(a.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0x1f, 0x8b, 0x08, 0x00})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	records, err = a.Records()
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+}
+
+func TestArchive_compact(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, err := New(filepath.Join(t.TempDir(), "history.archive"), Options{
+		MaxAge:  48 * time.Hour,
+		MaxSize: 8,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, a.Append([]Record{
+		record("old", start),
+		record("one", start.Add(24*time.Hour)),
+		record("two", start.Add(25*time.Hour)),
+		record("one", start.Add(26*time.Hour)),
+		record("three", start.Add(27*time.Hour)),
+	}))
+
+	dropped, err := a.Compact(start.Add(60 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, dropped)
+
+	records, err := a.Records()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "three"}, contents(records))
+
+	dropped, err = a.Compact(start.Add(60 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+}
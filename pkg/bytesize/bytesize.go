@@ -0,0 +1,95 @@
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	B   Size = 1
+	KiB Size = 1 << (10 * iota)
+	MiB
+	GiB
+)
+
+// Size is a number of bytes that can be written with binary units, such as 512K or 10MiB
+type Size int64
+
+var units = map[string]Size{
+	"":    B,
+	"b":   B,
+	"k":   KiB,
+	"kb":  KiB,
+	"kib": KiB,
+	"m":   MiB,
+	"mb":  MiB,
+	"mib": MiB,
+	"g":   GiB,
+	"gb":  GiB,
+	"gib": GiB,
+}
+
+// Parse reads a size such as 1024, 512K, 1.5MiB or 2G. Units are always powers of 1024.
+func Parse(s string) (Size, error) {
+	value := strings.TrimSpace(s)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := value, ""
+	if i >= 0 {
+		number, unit = value[:i], strings.TrimSpace(value[i:])
+	}
+
+	multiplier, ok := units[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q, unknown unit %q", s, unit)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return Size(n * float64(multiplier)), nil
+}
+
+// String formats the size with the largest unit that keeps it above 1
+func (s Size) String() string {
+	switch {
+	case s >= GiB:
+		return format(float64(s)/float64(GiB), "GiB")
+	case s >= MiB:
+		return format(float64(s)/float64(MiB), "MiB")
+	case s >= KiB:
+		return format(float64(s)/float64(KiB), "KiB")
+	default:
+		return strconv.FormatInt(int64(s), 10) + "B"
+	}
+}
+
+// format keeps at most one decimal
+func format(n float64, unit string) string {
+	return strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64) + unit
+}
+
+// Set implements pflag.Value
+func (s *Size) Set(value string) error {
+	size, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// Type implements pflag.Value
+func (s *Size) Type() string {
+	return "size"
+}
+
+// UnmarshalYAML reads sizes such as 100MiB from configuration files
+func (s *Size) UnmarshalYAML(value *yaml.Node) error {
+	return s.Set(value.Value)
+}
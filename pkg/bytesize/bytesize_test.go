@@ -0,0 +1,56 @@
+package bytesize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Size
+	}{
+		{in: "1024", want: 1024},
+		{in: "12b", want: 12},
+		{in: "512K", want: 512 * KiB},
+		{in: "10MiB", want: 10 * MiB},
+		{in: "1.5 mb", want: 1536 * KiB},
+		{in: "2G", want: 2 * GiB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, invalid := range []string{"", "ten", "10X", "-1K", "1.2.3M"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSize_String(t *testing.T) {
+	assert.Equal(t, "12B", Size(12).String())
+	assert.Equal(t, "512KiB", (512 * KiB).String())
+	assert.Equal(t, "1.5MiB", (1536 * KiB).String())
+	assert.Equal(t, "2GiB", (2 * GiB).String())
+}
+
+func TestSize_UnmarshalYAML(t *testing.T) {
+	var config struct {
+		Images Size `yaml:"images"`
+		Text   Size `yaml:"text"`
+	}
+	err := yaml.Unmarshal([]byte("images: 100MiB\ntext: 4096\n"), &config)
+	require.NoError(t, err)
+	assert.Equal(t, 100*MiB, config.Images)
+	assert.Equal(t, 4*KiB, config.Text)
+
+	err = yaml.Unmarshal([]byte("images: lots\n"), &config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"blueclip/pkg/archive"
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/duration"
+	"blueclip/pkg/hooks"
+	"blueclip/pkg/logging"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Hooks     []hooks.Hook    `yaml:"hooks"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Logging   logging.Config  `yaml:"logging"`
+	Copy      CopyConfig      `yaml:"copy"`
+	Policy    PolicyConfig    `yaml:"policy"`
+	Retention RetentionConfig `yaml:"retention"`
+	Archive   ArchiveConfig   `yaml:"archive"`
+	Trash     TrashConfig     `yaml:"trash"`
+}
+
+// SelectionOptions returns the limits for the selections
+func (c *Config) SelectionOptions() selections.Options {
+	options := c.Retention.Options()
+	options.TrashRetention = c.Trash.RetentionOrDefault()
+	return options
+}
+
+// TrashConfig keeps cleared selections for a while so a clear can be undone. It is enabled by default.
+type TrashConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// Retention is how long cleared selections are kept, 7d by default
+	Retention duration.Duration `yaml:"retention"`
+}
+
+func (t TrashConfig) Validate() error {
+	if t.Retention < 0 {
+		return fmt.Errorf("trash retention must not be negative")
+	}
+	return nil
+}
+
+// RetentionOrDefault returns the retention, zero if the trash is disabled
+func (t TrashConfig) RetentionOrDefault() time.Duration {
+	switch {
+	case t.Disabled:
+		return 0
+	case t.Retention == 0:
+		return selections.DefaultOptions().TrashRetention
+	default:
+		return time.Duration(t.Retention)
+	}
+}
+
+// ArchiveConfig keeps the selections dropped by the retention limits and the policy.
+// It is enabled by default.
+type ArchiveConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// Path defaults to the history file with the .archive extension
+	Path string `yaml:"path"`
+	// MaxAge drops archived selections older than this, 30d by default
+	MaxAge duration.Duration `yaml:"max_age"`
+	// MaxSize limits the bytes archived, the oldest are dropped first. 100MiB by default
+	MaxSize bytesize.Size `yaml:"max_size"`
+}
+
+const (
+	DefaultArchiveMaxAge  = 30 * 24 * time.Hour
+	DefaultArchiveMaxSize = 100 * bytesize.MiB
+)
+
+func (a ArchiveConfig) Validate() error {
+	if a.MaxAge < 0 || a.MaxSize < 0 {
+		return fmt.Errorf("archive max_age and max_size must not be negative")
+	}
+	return nil
+}
+
+// Options returns the archive limits
+func (a ArchiveConfig) Options() archive.Options {
+	options := archive.Options{
+		MaxAge:  DefaultArchiveMaxAge,
+		MaxSize: DefaultArchiveMaxSize,
+	}
+	if a.MaxAge > 0 {
+		options.MaxAge = time.Duration(a.MaxAge)
+	}
+	if a.MaxSize > 0 {
+		options.MaxSize = a.MaxSize
+	}
+	return options
+}
+
+// RetentionConfig limits how much history is kept, the oldest selections are dropped first.
+// Lowering a limit drops the selections over it on the next start.
+type RetentionConfig struct {
+	// MaxEphemeral and MaxImportant limit the number of selections, 0 uses the default
+	MaxEphemeral int `yaml:"max_ephemeral"`
+	MaxImportant int `yaml:"max_important"`
+	// EphemeralSize, ImportantSize and TotalSize limit the bytes used, 0 is unlimited
+	EphemeralSize bytesize.Size `yaml:"ephemeral_size"`
+	ImportantSize bytesize.Size `yaml:"important_size"`
+	TotalSize     bytesize.Size `yaml:"total_size"`
+	// TargetSize limits the bytes used by each target, such as image/png
+	TargetSize map[xclip.ValidTarget]bytesize.Size `yaml:"target_size"`
+}
+
+func (r RetentionConfig) Validate() error {
+	if r.MaxEphemeral < 0 || r.MaxImportant < 0 {
+		return fmt.Errorf("retention max_ephemeral and max_important must not be negative")
+	}
+	for target := range r.TargetSize {
+		if target == "" {
+			return fmt.Errorf("retention target_size has an empty target")
+		}
+	}
+	return nil
+}
+
+// Options returns the limits for the selections
+func (r RetentionConfig) Options() selections.Options {
+	options := selections.DefaultOptions()
+	if r.MaxEphemeral > 0 {
+		options.MaxEphemeralElements = r.MaxEphemeral
+	}
+	if r.MaxImportant > 0 {
+		options.MaxImportantElements = r.MaxImportant
+	}
+	options.Budget = selections.Budget{
+		Ephemeral: r.EphemeralSize,
+		Important: r.ImportantSize,
+		Total:     r.TotalSize,
+		Targets:   r.TargetSize,
+	}
+	return options
+}
+
+type CopyConfig struct {
+	// KeepCategory keeps copied selections in their category instead of moving them
+	// to the important list, clients can override it on every copy
+	KeepCategory bool `yaml:"keep_category"`
+}
+
+// PolicyConfig moves selections between categories automatically, every rule is disabled by default.
+// It is evaluated on every capture and copy and periodically.
+type PolicyConfig struct {
+	// PromoteAfterCopies promotes ephemeral selections copied this many times
+	PromoteAfterCopies int `yaml:"promote_after_copies"`
+	// PromoteAfterCaptures promotes ephemeral selections captured this many times
+	PromoteAfterCaptures int `yaml:"promote_after_captures"`
+	// DemoteAfter demotes important selections not used for this long back to ephemeral
+	DemoteAfter duration.Duration `yaml:"demote_after"`
+	// ExpireAfter removes ephemeral selections not used for this long
+	ExpireAfter duration.Duration `yaml:"expire_after"`
+	// Interval is how often the policy is evaluated besides captures and copies, 1h by default
+	Interval duration.Duration `yaml:"interval"`
+}
+
+// DefaultPolicyInterval is used when the policy doesn't set an interval
+const DefaultPolicyInterval = time.Hour
+
+func (p PolicyConfig) Validate() error {
+	for name, count := range map[string]int{
+		"promote_after_copies":   p.PromoteAfterCopies,
+		"promote_after_captures": p.PromoteAfterCaptures,
+	} {
+		if count < 0 || count > selections.MaxUses {
+			return fmt.Errorf("policy %s must be between 0 and %d, got %d", name, selections.MaxUses, count)
+		}
+	}
+	for name, d := range map[string]duration.Duration{
+		"demote_after": p.DemoteAfter,
+		"expire_after": p.ExpireAfter,
+		"interval":     p.Interval,
+	} {
+		if d < 0 {
+			return fmt.Errorf("policy %s must not be negative, got %s", name, time.Duration(d))
+		}
+	}
+	return nil
+}
+
+// Policy returns the rules to apply to the selections
+func (p PolicyConfig) Policy() selections.Policy {
+	return selections.Policy{
+		PromoteAfterCopies:   p.PromoteAfterCopies,
+		PromoteAfterCaptures: p.PromoteAfterCaptures,
+		DemoteAfter:          time.Duration(p.DemoteAfter),
+		ExpireAfter:          time.Duration(p.ExpireAfter),
+	}
+}
+
+// EvaluationInterval returns the interval or the default one
+func (p PolicyConfig) EvaluationInterval() time.Duration {
+	if p.Interval == 0 {
+		return DefaultPolicyInterval
+	}
+	return time.Duration(p.Interval)
+}
+
+type MetricsConfig struct {
+	// Listen is an optional localhost TCP address where /metrics is exposed,
+	// metrics are always available on the unix socket
+	Listen string `yaml:"listen"`
+}
+
+func (m MetricsConfig) Validate() error {
+	if m.Listen == "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(m.Listen)
+	if err != nil {
+		return fmt.Errorf("invalid metrics listen address: %v", err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("metrics listen address must be a loopback address, got %s", host)
+	}
+	return nil
+}
+
+// Load reads the configuration file at the given path.
+// A missing file is not an error, the default configuration is returned instead.
+func Load(path string) (*Config, error) {
+	// Resolve ~ to user's home directory
+	if len(path) > 1 && path[:2] == "~/" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %v", err)
+		}
+		path = home + path[1:]
+	}
+
+	cfg := &Config{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to open config file: %v", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	err = dec.Decode(cfg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode config file: %v", err)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	for _, h := range c.Hooks {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.Metrics.Validate(); err != nil {
+		return err
+	}
+	if err := c.Policy.Validate(); err != nil {
+		return err
+	}
+	if err := c.Retention.Validate(); err != nil {
+		return err
+	}
+	if err := c.Archive.Validate(); err != nil {
+		return err
+	}
+	if err := c.Trash.Validate(); err != nil {
+		return err
+	}
+	return c.Logging.Validate()
+}
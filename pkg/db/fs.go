@@ -2,6 +2,7 @@ package db
 
 import (
 	"blueclip/pkg/selections"
+	"bytes"
 	"encoding/gob"
 	"fmt"
 	"os"
@@ -46,7 +47,14 @@ func (db *FileDB) Load(s *selections.Set) error {
 	return nil
 }
 
+// Save writes a snapshot of the set taken under its lock, the set can change while the file is written
 func (db *FileDB) Save(s *selections.Set) error {
+	snapshot := bytes.Buffer{}
+	err := s.Encode(&snapshot)
+	if err != nil {
+		return err
+	}
+
 	os.MkdirAll(filepath.Dir(db.Path), 0755)
 
 	f, err := os.Create(db.Path)
@@ -55,10 +63,9 @@ func (db *FileDB) Save(s *selections.Set) error {
 	}
 	defer f.Close()
 
-	enc := gob.NewEncoder(f)
-	err = enc.Encode(s)
+	_, err = snapshot.WriteTo(f)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to write file: %v", err)
 	}
 	return nil
 }
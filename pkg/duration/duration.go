@@ -0,0 +1,39 @@
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that also accepts days, such as 30d
+type Duration time.Duration
+
+// Parse reads a duration such as 12h or 30d
+func Parse(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %s", value)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+	return d, nil
+}
+
+// UnmarshalYAML reads durations such as 30d from configuration files
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := Parse(value.Value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,42 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "90s", want: 90 * time.Second},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "1.5d", want: 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, in := range []string{"", "soon", "xd"} {
+		_, err := Parse(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestDuration_UnmarshalYAML(t *testing.T) {
+	var config struct {
+		MaxAge Duration `yaml:"max_age"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("max_age: 7d"), &config))
+	assert.Equal(t, Duration(7*24*time.Hour), config.MaxAge)
+}
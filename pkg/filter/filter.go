@@ -0,0 +1,310 @@
+package filter
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/duration"
+	"blueclip/pkg/search"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fields are the names accepted before a colon, such as target:image/png
+var Fields = []string{"target", "category", "age", "size", "from", "label", "tag", "collection"}
+
+// textTargets are matched by target:text besides the text/* targets
+var textTargets = []xclip.ValidTarget{
+	xclip.ValidTargetUTF8_STRING,
+	xclip.ValidTargetSTRING,
+	xclip.ValidTargetTEXT,
+	xclip.ValidTargetCOMPOUND_TEXT,
+}
+
+// Filter is a parsed query such as `target:image age:<2h "docker run"`, an entry must match every term
+type Filter struct {
+	terms []term
+	// category is the last category term, empty without one
+	category selections.SelectionRetentionType
+}
+
+type term func(entry selections.Entry, at time.Time) bool
+
+// ParseError points at the token that could not be parsed
+type ParseError struct {
+	Input string
+	// Offset is where the token starts in the input, in bytes
+	Offset int
+	Token  string
+	Reason string
+}
+
+// Error describes the problem and underlines the token in the input
+func (e *ParseError) Error() string {
+	column := utf8.RuneCountInString(e.Input[:e.Offset])
+	width := max(1, utf8.RuneCountInString(e.Token))
+	return fmt.Sprintf("invalid filter %q at column %d: %s\n%s\n%s%s",
+		e.Token, column+1, e.Reason, e.Input, strings.Repeat(" ", column), strings.Repeat("^", width))
+}
+
+type token struct {
+	// offset is where the token starts in the input, in bytes
+	offset int
+	text   string
+	// field is empty for free text
+	field string
+	value string
+}
+
+// Parse reads a query made of field:value terms and free text. Free text is matched
+// as a substring of the content with smart case, quote it to include spaces.
+// An empty query matches every entry.
+func Parse(input string) (*Filter, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Filter{}
+	for _, tok := range tokens {
+		t, reason := parseTerm(tok)
+		if reason != "" {
+			return nil, &ParseError{Input: input, Offset: tok.offset, Token: tok.text, Reason: reason}
+		}
+		f.terms = append(f.terms, t)
+		if tok.field == "category" {
+			f.category = selections.SelectionRetentionType(tok.value)
+		}
+	}
+	return f, nil
+}
+
+// Category returns the category every matching entry belongs to, empty if the query has no category term
+func (f *Filter) Category() selections.SelectionRetentionType {
+	return f.category
+}
+
+// Matches reports whether the entry passes every term, the age is measured at the given time
+func (f *Filter) Matches(entry selections.Entry, at time.Time) bool {
+	for _, t := range f.terms {
+		if !t(entry, at) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenize(input string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		start := i
+		field := ""
+		if n := fieldLength(input[i:]); n > 0 {
+			field = input[i : i+n]
+			i += n + 1
+		}
+		value, end, ok := readValue(input, i)
+		if !ok {
+			return nil, &ParseError{Input: input, Offset: start, Token: input[start:], Reason: "missing closing quote"}
+		}
+		tokens = append(tokens, token{offset: start, text: input[start:end], field: field, value: value})
+		i = end
+	}
+	return tokens, nil
+}
+
+// fieldLength returns the length of the field name if s starts with one followed by a colon
+func fieldLength(s string) int {
+	for i, r := range s {
+		if r == ':' {
+			return i
+		}
+		if r < 'a' || r > 'z' {
+			return 0
+		}
+	}
+	return 0
+}
+
+// readValue reads a quoted or bare value starting at i and returns it with the offset after it.
+// In quotes a backslash escapes the next rune. ok is false if the closing quote is missing.
+func readValue(input string, i int) (value string, end int, ok bool) {
+	if i < len(input) && input[i] == '"' {
+		out := &strings.Builder{}
+		escaped := false
+		for j, r := range input[i+1:] {
+			switch {
+			case escaped:
+				out.WriteRune(r)
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				return out.String(), i + 1 + j + 1, true
+			default:
+				out.WriteRune(r)
+			}
+		}
+		return "", len(input), false
+	}
+
+	end = strings.IndexFunc(input[i:], unicode.IsSpace)
+	if end < 0 {
+		return input[i:], len(input), true
+	}
+	return input[i : i+end], i + end, true
+}
+
+// parseTerm returns the term for the token or the reason it is invalid
+func parseTerm(tok token) (term, string) {
+	if tok.value == "" {
+		if tok.field == "" {
+			return nil, "empty text"
+		}
+		return nil, fmt.Sprintf("missing value for %s", tok.field)
+	}
+
+	switch tok.field {
+	case "":
+		matcher, err := search.New(search.ModeSubstring, tok.value)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return func(entry selections.Entry, at time.Time) bool {
+			_, ok := matcher.Match(string(entry.Clean()))
+			return ok
+		}, ""
+	case "target":
+		return parseTarget(tok.value)
+	case "category":
+		category := selections.SelectionRetentionType(tok.value)
+		switch category {
+		case selections.SelectionRetentionTypeEphemeral,
+			selections.SelectionRetentionTypeImportant,
+			selections.SelectionRetentionTypePinned,
+			selections.SelectionRetentionTypeLast:
+		default:
+			return nil, "unknown category, allowed categories are: ephemeral, important, pinned, last"
+		}
+		return func(entry selections.Entry, at time.Time) bool {
+			return entry.Category == category
+		}, ""
+	case "age":
+		op, value := cutComparison(tok.value)
+		if op == "" {
+			return nil, "age needs a comparison such as age:<2h or age:>7d"
+		}
+		age, err := duration.Parse(value)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return func(entry selections.Entry, at time.Time) bool {
+			// Selections stored by older versions have no capture time and are always older
+			if entry.CapturedAt.IsZero() {
+				return op == ">" || op == ">="
+			}
+			return compare(op, int64(at.Sub(entry.CapturedAt)), int64(age))
+		}, ""
+	case "size":
+		op, value := cutComparison(tok.value)
+		if op == "" {
+			op = "="
+		}
+		size, err := bytesize.Parse(value)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return func(entry selections.Entry, at time.Time) bool {
+			return compare(op, int64(len(entry.Content)), int64(size))
+		}, ""
+	case "from":
+		clip := xclip.ClipboardSelection(tok.value)
+		switch clip {
+		case xclip.ClipboardSelectionClipboard,
+			xclip.ClipboardSelectionPrimary,
+			xclip.ClipboardSelectionSecondary:
+		default:
+			return nil, "unknown selection, allowed selections are: clipboard, primary, secondary"
+		}
+		return func(entry selections.Entry, at time.Time) bool {
+			return entry.Clipboard == clip
+		}, ""
+	case "label":
+		label := strings.ToLower(tok.value)
+		return func(entry selections.Entry, at time.Time) bool {
+			return strings.Contains(strings.ToLower(entry.Label), label)
+		}, ""
+	case "tag":
+		return func(entry selections.Entry, at time.Time) bool {
+			return slices.Contains(entry.Tags, tok.value)
+		}, ""
+	case "collection":
+		return func(entry selections.Entry, at time.Time) bool {
+			return slices.Contains(entry.Collections, tok.value)
+		}, ""
+	default:
+		return nil, fmt.Sprintf("unknown field %s, allowed fields are: %v, quote the text to search for it", tok.field, Fields)
+	}
+}
+
+// parseTarget matches a target by its name or by its media type, such as image for image/png.
+// text also matches the x11 text targets.
+func parseTarget(value string) (term, string) {
+	matches := func(target xclip.ValidTarget) bool {
+		name := string(target)
+		if strings.EqualFold(name, value) {
+			return true
+		}
+		mediaType, _, found := strings.Cut(name, "/")
+		if found && strings.EqualFold(mediaType, value) {
+			return true
+		}
+		return strings.EqualFold(value, "text") && slices.Contains(textTargets, target)
+	}
+
+	known := false
+	for _, target := range xclip.ValidTargets {
+		known = known || matches(target)
+	}
+	if !known {
+		return nil, "unknown target, use a target such as image/png or a media type such as image"
+	}
+	return func(entry selections.Entry, at time.Time) bool {
+		return matches(entry.Target)
+	}, ""
+}
+
+// cutComparison splits a leading comparison operator from the value, op is empty if there is none
+func cutComparison(value string) (op, rest string) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			return op, rest
+		}
+	}
+	return "", value
+}
+
+func compare(op string, a, b int64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	default:
+		return a == b
+	}
+}
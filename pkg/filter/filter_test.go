@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	command := selections.Entry{
+		Selection: selections.Selection{
+			Selection:  xclip.NewSelection([]byte("docker run --rm alpine"), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionPrimary),
+			CapturedAt: at.Add(-time.Hour),
+			Label:      "Terminal",
+			Tags:       []string{"work", "docker"},
+		},
+		Category: selections.SelectionRetentionTypeImportant,
+	}
+	image := selections.Entry{
+		Selection: selections.Selection{
+			Selection:   xclip.NewSelection(make([]byte, 2048), xclip.ValidTargetImagePng, xclip.ClipboardSelectionClipboard),
+			CapturedAt:  at.Add(-10 * 24 * time.Hour),
+			Collections: []string{"screenshots"},
+		},
+		Category: selections.SelectionRetentionTypeEphemeral,
+	}
+
+	tests := []struct {
+		query string
+		want  []bool
+	}{
+		{query: "", want: []bool{true, true}},
+		{query: "target:image", want: []bool{false, true}},
+		{query: "target:text", want: []bool{true, false}},
+		{query: "target:image/png size:>1k", want: []bool{false, true}},
+		{query: "age:<2h category:important", want: []bool{true, false}},
+		{query: "age:>=7d", want: []bool{false, true}},
+		{query: "from:primary label:term", want: []bool{true, false}},
+		{query: `"docker run" alpine`, want: []bool{true, false}},
+		{query: `"docker  run"`, want: []bool{false, false}},
+		{query: "size:2KiB", want: []bool{false, true}},
+		{query: "tag:work tag:docker", want: []bool{true, false}},
+		{query: "tag:wor", want: []bool{false, false}},
+		{query: "collection:screenshots", want: []bool{false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			f, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, []bool{f.Matches(command, at), f.Matches(image, at)})
+		})
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{
+			query: "target:image age:<2x",
+			err:   "invalid filter \"age:<2x\" at column 14: invalid duration 2x\ntarget:image age:<2x\n             ^^^^^^^",
+		},
+		{
+			query: "http://example.com",
+			err:   "invalid filter \"http://example.com\" at column 1: unknown field http, allowed fields are: [target category age size from label tag collection], quote the text to search for it\nhttp://example.com\n^^^^^^^^^^^^^^^^^^",
+		},
+		{
+			query: `size:>1k "docker run`,
+			err:   "invalid filter \"\\\"docker run\" at column 10: missing closing quote\nsize:>1k \"docker run\n         ^^^^^^^^^^^",
+		},
+		{query: "age:2h", err: "invalid filter \"age:2h\" at column 1: age needs a comparison such as age:<2h or age:>7d\nage:2h\n^^^^^^"},
+		{query: "category:all", err: "invalid filter \"category:all\" at column 1: unknown category, allowed categories are: ephemeral, important, pinned, last\ncategory:all\n^^^^^^^^^^^^"},
+		{query: "target:video", err: "invalid filter \"target:video\" at column 1: unknown target, use a target such as image/png or a media type such as image\ntarget:video\n^^^^^^^^^^^^"},
+		{query: "from:", err: "invalid filter \"from:\" at column 1: missing value for from\nfrom:\n^^^^^"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.err, err.Error())
+		})
+	}
+}
+
+func TestFilter_Category(t *testing.T) {
+	f, err := Parse("category:pinned docker")
+	require.NoError(t, err)
+	assert.Equal(t, selections.SelectionRetentionTypePinned, f.Category())
+
+	f, err = Parse("docker")
+	require.NoError(t, err)
+	assert.Empty(t, f.Category())
+}
@@ -0,0 +1,132 @@
+package fuzzy
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxWindows is the number of candidate matches scored, the best one is kept
+const maxWindows = 8
+
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 24
+	bonusWordStart   = 12
+	bonusFirstRune   = 8
+	penaltyGap       = 1
+)
+
+// Match reports whether every rune of pattern appears in text in order.
+// It uses smart case, the match is case sensitive only if the pattern has upper case runes.
+// The score is higher for better matches and positions holds the byte offset of
+// every matched rune in text.
+func Match(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	caseSensitive := false
+	for _, r := range pattern {
+		if unicode.IsUpper(r) {
+			caseSensitive = true
+			break
+		}
+	}
+	fold := func(r rune) rune {
+		if caseSensitive {
+			return r
+		}
+		return unicode.ToLower(r)
+	}
+
+	best := -1
+	from := 0
+	// Every window is found with a forward pass and tightened with a backward pass.
+	// Only the first windows are scored so large texts stay linear.
+	for i := 0; i < maxWindows && from < len(text); i++ {
+		_, forward, matched := matchFrom(pattern, text, from, fold)
+		if !matched {
+			break
+		}
+		start := tighten(pattern, text, forward[len(forward)-1], fold)
+		s, p, _ := matchFrom(pattern, text, start, fold)
+		if s > best {
+			best = s
+			positions = p
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		from = start + size
+	}
+	if best < 0 {
+		return 0, nil, false
+	}
+	return best, positions, true
+}
+
+// tighten walks back from the offset of the last matched rune and returns the latest offset
+// from which the whole pattern still matches
+func tighten(pattern, text string, last int, fold func(rune) rune) int {
+	_, size := utf8.DecodeRuneInString(text[last:])
+	offset := last + size
+	rest := pattern
+	for len(rest) > 0 {
+		pr, size := utf8.DecodeLastRuneInString(rest)
+		rest = rest[:len(rest)-size]
+		pr = fold(pr)
+		for {
+			r, size := utf8.DecodeLastRuneInString(text[:offset])
+			offset -= size
+			if fold(r) == pr {
+				break
+			}
+		}
+	}
+	return offset
+}
+
+func matchFrom(pattern, text string, start int, fold func(rune) rune) (int, []int, bool) {
+	positions := make([]int, 0, utf8.RuneCountInString(pattern))
+	score := 0
+	offset := start
+	previous := -1
+
+	for _, pr := range pattern {
+		pr = fold(pr)
+		found := false
+		for offset < len(text) {
+			r, size := utf8.DecodeRuneInString(text[offset:])
+			if fold(r) == pr {
+				score += scoreMatch
+				if previous >= 0 && offset == previous {
+					score += bonusConsecutive
+				} else if previous >= 0 {
+					score -= penaltyGap * min(offset-previous, 32)
+				}
+				if isWordStart(text, offset) {
+					score += bonusWordStart
+				}
+				if offset == 0 {
+					score += bonusFirstRune
+				}
+				positions = append(positions, offset)
+				offset += size
+				previous = offset
+				found = true
+				break
+			}
+			offset += size
+		}
+		if !found {
+			return 0, nil, false
+		}
+	}
+	return score, positions, true
+}
+
+func isWordStart(text string, offset int) bool {
+	if offset == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:offset])
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev)
+}
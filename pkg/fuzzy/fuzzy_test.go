@@ -0,0 +1,49 @@
+package fuzzy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern, text string
+		ok            bool
+		positions     []int
+	}{
+		{name: "empty pattern matches everything", pattern: "", text: "anything", ok: true},
+		{name: "subsequence", pattern: "dkr", text: "docker run", ok: true, positions: []int{0, 3, 5}},
+		{name: "missing rune", pattern: "dkx", text: "docker run", ok: false},
+		{name: "smart case insensitive", pattern: "dock", text: "Docker", ok: true, positions: []int{0, 1, 2, 3}},
+		{name: "smart case sensitive", pattern: "Dock", text: "docker", ok: false},
+		{name: "prefers consecutive match", pattern: "run", text: "r u n run", ok: true, positions: []int{6, 7, 8}},
+		{name: "multibyte runes", pattern: "ñu", text: "año ñu", ok: true, positions: []int{5, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := Match(tt.pattern, tt.text)
+			require.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.positions, positions)
+		})
+	}
+}
+
+func TestMatch_score_prefers_word_starts(t *testing.T) {
+	wordStart, _, ok := Match("gc", "git commit")
+	require.True(t, ok)
+	middle, _, ok := Match("gc", "logical")
+	require.True(t, ok)
+	assert.Greater(t, wordStart, middle)
+}
+
+func TestMatch_large_text(t *testing.T) {
+	// Every rune starts a candidate match, trying all of them would be quadratic
+	text := strings.Repeat("a", 1<<20) + "b"
+	_, positions, ok := Match("ab", text)
+	require.True(t, ok)
+	assert.Equal(t, []int{1<<20 - 1, 1 << 20}, positions)
+}
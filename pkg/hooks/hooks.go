@@ -0,0 +1,205 @@
+package hooks
+
+import (
+	"blueclip/pkg/logging"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type Event string
+
+const (
+	EventCapture Event = "capture"
+	EventCopy    Event = "copy"
+	EventClear   Event = "clear"
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultConcurrency = 1
+	// maxPending is the number of executions that can wait for a free slot
+	// before new events are dropped.
+	maxPending = 64
+)
+
+// Hook is the user configuration of a command executed on clipboard events.
+// Empty filters match everything.
+type Hook struct {
+	Name    string   `yaml:"name"`
+	Command []string `yaml:"command"`
+
+	Events     []Event                             `yaml:"events"`
+	Targets    []xclip.ValidTarget                 `yaml:"targets"`
+	Categories []selections.SelectionRetentionType `yaml:"categories"`
+	// Match is a regular expression evaluated against the selection line
+	Match string `yaml:"match"`
+
+	Timeout     time.Duration `yaml:"timeout"`
+	Concurrency int           `yaml:"concurrency"`
+}
+
+// Validate checks that the hook can be executed
+func (h Hook) Validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("hook name is required")
+	}
+	if len(h.Command) == 0 {
+		return fmt.Errorf("hook %s: command is required", h.Name)
+	}
+	for _, e := range h.Events {
+		switch e {
+		case EventCapture, EventCopy, EventClear:
+		default:
+			return fmt.Errorf("hook %s: invalid event %s, valid values are: %v", h.Name, e, []Event{EventCapture, EventCopy, EventClear})
+		}
+	}
+	for _, c := range h.Categories {
+		switch c {
+		case selections.SelectionRetentionTypeEphemeral, selections.SelectionRetentionTypeImportant, selections.SelectionRetentionTypePinned:
+		default:
+			return fmt.Errorf("hook %s: invalid category %s", h.Name, c)
+		}
+	}
+	if _, err := regexp.Compile(h.Match); err != nil {
+		return fmt.Errorf("hook %s: invalid match expression: %v", h.Name, err)
+	}
+	if h.Timeout < 0 {
+		return fmt.Errorf("hook %s: timeout must be positive", h.Name)
+	}
+	if h.Concurrency < 0 {
+		return fmt.Errorf("hook %s: concurrency must be positive", h.Name)
+	}
+	return nil
+}
+
+// Payload describes the clipboard event that triggers the hooks
+type Payload struct {
+	Event      Event
+	Entry      selections.Entry
+	Clipboards []xclip.ClipboardSelection
+}
+
+type hook struct {
+	Hook
+	match   *regexp.Regexp
+	slots   chan struct{}
+	pending atomic.Int32
+}
+
+// Runner executes the configured hooks in the background
+type Runner struct {
+	hooks []*hook
+}
+
+func NewRunner(configured []Hook) (*Runner, error) {
+	r := &Runner{}
+	for _, h := range configured {
+		if err := h.Validate(); err != nil {
+			return nil, err
+		}
+		if h.Timeout == 0 {
+			h.Timeout = defaultTimeout
+		}
+		if h.Concurrency == 0 {
+			h.Concurrency = defaultConcurrency
+		}
+		r.hooks = append(r.hooks, &hook{
+			Hook:  h,
+			match: regexp.MustCompile(h.Match),
+			slots: make(chan struct{}, h.Concurrency),
+		})
+	}
+	return r, nil
+}
+
+func (h *hook) matches(p Payload) bool {
+	if len(h.Events) > 0 && !slices.Contains(h.Events, p.Event) {
+		return false
+	}
+	if len(h.Targets) > 0 && !slices.Contains(h.Targets, p.Entry.Target) {
+		return false
+	}
+	if len(h.Categories) > 0 && !slices.Contains(h.Categories, p.Entry.Category) {
+		return false
+	}
+	return h.match.Match(p.Entry.Clean())
+}
+
+// Fire runs every hook matching the payload. It never blocks, executions
+// that exceed the hook concurrency wait for a free slot in the background.
+func (r *Runner) Fire(ctx context.Context, p Payload) {
+	if r == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, h := range r.hooks {
+		if !h.matches(p) {
+			continue
+		}
+		if h.pending.Add(1) > maxPending {
+			h.pending.Add(-1)
+			slog.Warn("Hook has too many pending executions, dropping event", "hook", h.Name, "event", p.Event)
+			continue
+		}
+		go func() {
+			h.slots <- struct{}{}
+			defer func() {
+				<-h.slots
+				h.pending.Add(-1)
+			}()
+			if output, err := h.run(ctx, p); err != nil {
+				// The output may echo the clipboard content, it is logged like the content
+				slog.Warn("Hook failed", "hook", h.Name, "event", p.Event, "error", err, logging.Content("output", output))
+			}
+		}()
+	}
+}
+
+// run executes the hook and returns its combined output
+func (h *hook) run(ctx context.Context, p Payload) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	clipboards := make([]string, len(p.Clipboards))
+	for i, c := range p.Clipboards {
+		clipboards[i] = string(c)
+	}
+
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(p.Entry.Content)
+	cmd.Env = append(os.Environ(),
+		"BLUECLIP_HOOK="+h.Name,
+		"BLUECLIP_EVENT="+string(p.Event),
+		"BLUECLIP_TARGET="+string(p.Entry.Target),
+		"BLUECLIP_CATEGORY="+string(p.Entry.Category),
+		"BLUECLIP_SELECTION="+strings.Join(clipboards, ","),
+		"BLUECLIP_SIZE="+strconv.Itoa(len(p.Entry.Content)),
+	)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output.Bytes(), fmt.Errorf("timed out after %s", h.Timeout)
+	}
+	if err != nil {
+		return output.Bytes(), err
+	}
+	slog.Debug("Hook completed", "hook", h.Name, "event", p.Event, "duration", time.Since(start))
+	return output.Bytes(), nil
+}
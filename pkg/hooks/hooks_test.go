@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(content string, target xclip.ValidTarget, category selections.SelectionRetentionType) selections.Entry {
+	return selections.Entry{
+		Selection: selections.Selection{
+			Selection: xclip.NewSelection([]byte(content), target, xclip.ClipboardSelectionClipboard),
+		},
+		Category: category,
+	}
+}
+
+func TestHook_matches(t *testing.T) {
+	runner, err := NewRunner([]Hook{{
+		Name:       "urls",
+		Command:    []string{"true"},
+		Events:     []Event{EventCapture},
+		Targets:    []xclip.ValidTarget{xclip.ValidTargetUTF8_STRING},
+		Categories: []selections.SelectionRetentionType{selections.SelectionRetentionTypeEphemeral},
+		Match:      `^https?://`,
+	}})
+	require.NoError(t, err)
+	h := runner.hooks[0]
+
+	assert.True(t, h.matches(Payload{
+		Event: EventCapture,
+		Entry: entry("https://example.com", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeEphemeral),
+	}))
+	assert.False(t, h.matches(Payload{
+		Event: EventCopy,
+		Entry: entry("https://example.com", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeEphemeral),
+	}), "event does not match")
+	assert.False(t, h.matches(Payload{
+		Event: EventCapture,
+		Entry: entry("https://example.com", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeImportant),
+	}), "category does not match")
+	assert.False(t, h.matches(Payload{
+		Event: EventCapture,
+		Entry: entry("just some text", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeEphemeral),
+	}), "regex does not match")
+}
+
+func TestHook_invalid(t *testing.T) {
+	_, err := NewRunner([]Hook{{Name: "broken", Command: []string{"true"}, Match: "("}})
+	require.Error(t, err)
+
+	_, err = NewRunner([]Hook{{Name: "no-command"}})
+	require.Error(t, err)
+
+	_, err = NewRunner([]Hook{{Name: "bad-event", Command: []string{"true"}, Events: []Event{"paste"}}})
+	require.Error(t, err)
+}
+
+func TestRunner_Fire(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	runner, err := NewRunner([]Hook{{
+		Name:    "record",
+		Command: []string{"sh", "-c", `printf '%s %s %s ' "$BLUECLIP_EVENT" "$BLUECLIP_CATEGORY" "$BLUECLIP_SELECTION" > "$OUT"; cat >> "$OUT"`},
+	}})
+	require.NoError(t, err)
+	t.Setenv("OUT", out)
+
+	runner.Fire(context.Background(), Payload{
+		Event:      EventCapture,
+		Entry:      entry("hello", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeEphemeral),
+		Clipboards: []xclip.ClipboardSelection{xclip.ClipboardSelectionPrimary},
+	})
+
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(out)
+		return err == nil && string(content) == "capture ephemeral primary hello"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHook_run_failure_keeps_output_out_of_the_error(t *testing.T) {
+	runner, err := NewRunner([]Hook{{Name: "echo", Command: []string{"sh", "-c", "cat; exit 3"}}})
+	require.NoError(t, err)
+
+	output, err := runner.hooks[0].run(context.Background(), Payload{
+		Event: EventCapture,
+		Entry: entry("secret token", xclip.ValidTargetUTF8_STRING, selections.SelectionRetentionTypeEphemeral),
+	})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Equal(t, "secret token", string(output))
+}
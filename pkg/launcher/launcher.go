@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"blueclip/pkg/xclip"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLineLength is the number of runes kept from the content when building a line,
+// launchers don't deal well with huge rows
+const MaxLineLength = 256
+
+// Escape turns content into a single line.
+// Backslashes, line breaks, tabs and control characters are escaped so different contents
+// produce different lines unless they are truncated at max runes.
+func Escape(content string, max int) string {
+	b := strings.Builder{}
+	count := 0
+	for _, r := range content {
+		if max > 0 && count == max {
+			b.WriteString("…")
+			break
+		}
+		count++
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) || r == utf8.RuneError {
+				fmt.Fprintf(&b, `\x%02x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Line is the single line representation of an entry used by launchers
+func Line(content string) string {
+	return Escape(content, MaxLineLength)
+}
+
+// Find returns the index of the first line equal to chosen.
+// Trailing line breaks added by the launcher are ignored.
+func Find(lines []string, chosen string) (int, bool) {
+	chosen = strings.TrimRight(chosen, "\r\n")
+	for i, line := range lines {
+		if line == chosen {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Icon returns a freedesktop icon name that represents the target
+func Icon(target xclip.ValidTarget) string {
+	switch target {
+	case xclip.ValidTargetImagePng:
+		return "image-x-generic"
+	case xclip.ValidTargetxSpecialGnomeCopiedFiles,
+		xclip.ValidTargetTextUriList,
+		xclip.ValidTargetApplicationVndPortalFiles,
+		xclip.ValidTargetApplicationVndPortalFiletransfer:
+		return "folder"
+	default:
+		return "text-x-generic"
+	}
+}
+
+// Rofi return values passed in ROFI_RETV when running in script mode
+const (
+	RofiInitial      = 0
+	RofiSelected     = 1
+	RofiCustomInput  = 2
+	RofiCustomKey    = 10
+	RofiCustomKeyMax = 28
+)
+
+// RofiRow is an entry of the rofi script mode
+type RofiRow struct {
+	Text string
+	Icon string
+	// Info is passed back to the script in ROFI_INFO when the row is selected
+	Info string
+}
+
+// WriteRofiOption writes a mode option such as prompt or message
+func WriteRofiOption(w io.Writer, key, value string) error {
+	_, err := fmt.Fprintf(w, "\000%s\037%s\n", key, value)
+	return err
+}
+
+// WriteRofiRow writes a row, the text must be a single line as returned by Line
+func WriteRofiRow(w io.Writer, row RofiRow) error {
+	b := strings.Builder{}
+	b.WriteString(row.Text)
+	separator := byte('\000')
+	if row.Icon != "" {
+		b.WriteByte(separator)
+		fmt.Fprintf(&b, "icon\037%s", row.Icon)
+		separator = '\037'
+	}
+	if row.Info != "" {
+		b.WriteByte(separator)
+		fmt.Fprintf(&b, "info\037%s", row.Info)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package launcher
+
+import (
+	"blueclip/pkg/xclip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		max     int
+		want    string
+	}{
+		{name: "plain", content: "hello world", want: "hello world"},
+		{name: "line breaks", content: "a\nb\r\n", want: `a\nb\r\n`},
+		{name: "tabs", content: "a\tb", want: `a\tb`},
+		{name: "backslash", content: `a\nb`, want: `a\\nb`},
+		{name: "control characters", content: "a\000b\037", want: `a\x00b\x1f`},
+		{name: "multibyte", content: "año\nñu", want: `año\nñu`},
+		{name: "truncated", content: "abcdef", max: 3, want: "abc…"},
+		{name: "not truncated at max", content: "abc", max: 3, want: "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Escape(tt.content, tt.max))
+		})
+	}
+}
+
+func TestEscape_distinct_contents_produce_distinct_lines(t *testing.T) {
+	assert.NotEqual(t, Escape("a\nb", 0), Escape(`a\nb`, 0))
+	assert.NotEqual(t, Escape("a\\\nb", 0), Escape(`a\n\b`, 0))
+}
+
+func TestFind(t *testing.T) {
+	lines := []string{Line("first\nline"), Line("second"), Line("second")}
+
+	i, ok := Find(lines, `first\nline`+"\n")
+	require.True(t, ok)
+	assert.Equal(t, 0, i)
+
+	i, ok = Find(lines, "second")
+	require.True(t, ok)
+	assert.Equal(t, 1, i, "the first match wins")
+
+	_, ok = Find(lines, "missing")
+	assert.False(t, ok)
+}
+
+func TestWriteRofiRow(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, WriteRofiOption(out, "prompt", "blueclip"))
+	require.NoError(t, WriteRofiRow(out, RofiRow{Text: "hello", Icon: Icon(xclip.ValidTargetUTF8_STRING), Info: "abc123"}))
+	require.NoError(t, WriteRofiRow(out, RofiRow{Text: "no icon", Info: "def456"}))
+	require.NoError(t, WriteRofiRow(out, RofiRow{Text: "bare"}))
+
+	assert.Equal(t, strings.Join([]string{
+		"\000prompt\037blueclip",
+		"hello\000icon\037text-x-generic\037info\037abc123",
+		"no icon\000info\037def456",
+		"bare",
+		"",
+	}, "\n"), out.String())
+}
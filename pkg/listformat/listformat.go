@@ -0,0 +1,171 @@
+package listformat
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/launcher"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/selections"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// FirstLineLength is the number of runes kept in Row.FirstLine
+const FirstLineLength = 60
+
+// Presets are the named formats accepted by New, json is handled separately
+var Presets = map[string]string{
+	"oneline": `[{{.Short}}] {{printf "%-3s" .Age}} {{printf "%9s" (lines .Lines)}}  {{with .Label}}[{{escape .}}] {{end}}{{.FirstLine}}`,
+	"full":    `{{printf "%-3d" .Index}} {{.ID}} {{printf "%-9s" .Category}} {{printf "%-11s" .Target}} {{printf "%4s" .Age}} {{printf "%7s" (bytes .Size)}} {{printf "%9s" (lines .Lines)}}  {{with .Label}}[{{escape .}}] {{end}}{{.FirstLine}}`,
+	"json":    "",
+}
+
+// PresetNames returns the preset names sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Row holds the fields available to list templates
+type Row struct {
+	Index    int
+	ID       string
+	Category selections.SelectionRetentionType
+	// Short is the first letter of the category in upper case
+	Short  string
+	Target string
+	Size   int
+	Lines  int
+	// Age is a compact duration since the selection was captured, - if unknown
+	Age        string
+	CapturedAt time.Time
+	// CopiedAt is zero if the selection was never copied
+	CopiedAt time.Time
+	// Label describes the content, such as where it comes from or a name given by the user
+	Label string
+	Tags  []string
+	// Collections are the named collections the selection belongs to
+	Collections []string
+	// FirstLine is the escaped first line truncated to FirstLineLength runes
+	FirstLine string
+	// Line is the whole content escaped to a single line
+	Line string
+	// Content is the content as written by list
+	Content string
+}
+
+func NewRow(entry listing.Entry, now time.Time) Row {
+	lines := strings.Count(strings.TrimSuffix(entry.Line, "\n"), "\n") + 1
+	first, _, more := strings.Cut(entry.Line, "\n")
+	firstLine := launcher.Escape(first, FirstLineLength)
+	if more && !strings.HasSuffix(firstLine, "…") && strings.TrimSpace(entry.Line[len(first):]) != "" {
+		firstLine += "…"
+	}
+
+	return Row{
+		Index:       entry.Index,
+		ID:          entry.ID,
+		Category:    entry.Category,
+		Short:       strings.ToUpper(string(entry.Category)[:min(1, len(entry.Category))]),
+		Target:      string(entry.Target),
+		Size:        entry.Size,
+		Lines:       lines,
+		Age:         Age(entry.CapturedAt, now),
+		CapturedAt:  entry.CapturedAt,
+		CopiedAt:    entry.CopiedAt,
+		Label:       entry.Label,
+		Tags:        entry.Tags,
+		Collections: entry.Collections,
+		FirstLine:   firstLine,
+		Line:        launcher.Escape(entry.Line, 0),
+		Content:     entry.Line,
+	}
+}
+
+// Age formats the time since t with a single unit, such as 42s, 3m, 5h or 2d
+func Age(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := max(0, now.Sub(t))
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+var funcs = template.FuncMap{
+	"escape": func(s string) string {
+		return launcher.Escape(s, 0)
+	},
+	"trunc": func(n int, s string) string {
+		if utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		return string([]rune(s)[:n]) + "…"
+	},
+	"bytes": func(n int) string {
+		return bytesize.Size(n).String()
+	},
+	"lines": func(n int) string {
+		if n == 1 {
+			return "1 line"
+		}
+		return fmt.Sprintf("%d lines", n)
+	},
+}
+
+// Formatter writes list entries using a template or as JSON
+type Formatter struct {
+	tmpl *template.Template
+}
+
+// New parses format, which is either a preset name or a text/template executed for every Row
+func New(format string) (*Formatter, error) {
+	if preset, ok := Presets[format]; ok {
+		if preset == "" {
+			return &Formatter{}, nil
+		}
+		format = preset
+	}
+	tmpl, err := template.New("list").Funcs(funcs).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list format: %v", err)
+	}
+	return &Formatter{tmpl: tmpl}, nil
+}
+
+// Write writes every entry followed by a new line
+func (f *Formatter) Write(out io.Writer, entries []listing.Entry, now time.Time) error {
+	if f.tmpl == nil {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	w := bufio.NewWriter(out)
+	for _, entry := range entries {
+		err := f.tmpl.Execute(w, NewRow(entry, now))
+		if err != nil {
+			return fmt.Errorf("failed to format entry %d: %v", entry.Index, err)
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
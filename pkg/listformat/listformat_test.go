@@ -0,0 +1,86 @@
+package listformat
+
+import (
+	"blueclip/pkg/listing"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func entries() []listing.Entry {
+	code := "func main() {\n" + strings.Repeat("\tprintln()\n", 40) + "}\n"
+	return []listing.Entry{
+		{
+			Index:      0,
+			ID:         "aaaaaaaaaaaa",
+			Category:   selections.SelectionRetentionTypeImportant,
+			Target:     xclip.ValidTargetUTF8_STRING,
+			Size:       len(code),
+			CapturedAt: now.Add(-3 * time.Minute),
+			Line:       code,
+		},
+		{
+			Index:    1,
+			ID:       "bbbbbbbbbbbb",
+			Category: selections.SelectionRetentionTypeEphemeral,
+			Target:   xclip.ValidTargetUTF8_STRING,
+			Size:     5,
+			Line:     "hello",
+		},
+	}
+}
+
+func TestFormatter_oneline(t *testing.T) {
+	f, err := New("oneline")
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Write(out, entries(), now))
+	assert.Equal(t, "[I] 3m   42 lines  func main() {…\n[E] -      1 line  hello\n", out.String())
+}
+
+func TestFormatter_template(t *testing.T) {
+	f, err := New(`{{.Index}} {{.ID}} {{.Target}} {{bytes .Size}} {{trunc 5 .Line}}`)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Write(out, entries(), now))
+	assert.Equal(t, "0 aaaaaaaaaaaa UTF8_STRING 456B func …\n1 bbbbbbbbbbbb UTF8_STRING 5B hello\n", out.String())
+}
+
+func TestFormatter_json(t *testing.T) {
+	f, err := New("json")
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, f.Write(out, entries(), now))
+
+	decoded := []listing.Entry{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, entries()[1], decoded[1])
+}
+
+func TestFormatter_invalid_template(t *testing.T) {
+	_, err := New("{{.Missing")
+	assert.Error(t, err)
+
+	f, err := New("{{.Missing}}")
+	require.NoError(t, err)
+	assert.Error(t, f.Write(&bytes.Buffer{}, entries(), now))
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "-", Age(time.Time{}, now))
+	assert.Equal(t, "42s", Age(now.Add(-42*time.Second), now))
+	assert.Equal(t, "5h", Age(now.Add(-5*time.Hour-30*time.Minute), now))
+	assert.Equal(t, "2d", Age(now.Add(-50*time.Hour), now))
+}
@@ -0,0 +1,44 @@
+package listing
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"time"
+)
+
+// Entry is the JSON representation of an entry in the list
+type Entry struct {
+	// Index is the position in the list, the last selection is 0
+	Index    int                               `json:"index"`
+	ID       string                            `json:"id"`
+	Category selections.SelectionRetentionType `json:"category"`
+	Target   xclip.ValidTarget                 `json:"target"`
+	Size     int                               `json:"size"`
+	// CapturedAt is zero if the selection was stored by an older version
+	CapturedAt time.Time `json:"captured_at"`
+	// CopiedAt is zero if the selection was never copied
+	CopiedAt time.Time `json:"copied_at"`
+	Label    string    `json:"label,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+	// Collections are the named collections the selection belongs to
+	Collections []string `json:"collections,omitempty"`
+	// Line is the content as written by list, images are replaced by a description
+	Line string `json:"line"`
+}
+
+// NewEntry describes the entry at the index of the list
+func NewEntry(index int, entry selections.Entry) Entry {
+	return Entry{
+		Index:       index,
+		ID:          entry.ID(),
+		Category:    entry.Category,
+		Target:      entry.Target,
+		Size:        len(entry.Content),
+		CapturedAt:  entry.CapturedAt,
+		CopiedAt:    entry.LastCopiedAt(),
+		Label:       entry.Label,
+		Tags:        entry.Tags,
+		Collections: entry.Collections,
+		Line:        string(entry.Clean()),
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+type Config struct {
+	// Level is one of debug, info, warn or error
+	Level string `yaml:"level"`
+	// Format is text or json, json is convenient for journald
+	Format Format `yaml:"format"`
+	// Content allows clipboard content in debug logs,
+	// by default only its size and hash are logged
+	Content bool `yaml:"content"`
+}
+
+func (c Config) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+	switch c.Format {
+	case "", FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("invalid log format %s, valid values are: %v", c.Format, []Format{FormatText, FormatJSON})
+	}
+	return nil
+}
+
+var (
+	level   = new(slog.LevelVar)
+	content atomic.Bool
+)
+
+// Setup installs the default logger, the standard log package is redirected to it too
+func Setup(out io.Writer, cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	l, _ := ParseLevel(cfg.Level)
+	SetLevel(l)
+	SetContent(cfg.Content)
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		handler = slog.NewTextHandler(out, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// ParseLevel parses a level name, empty means info
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return l, fmt.Errorf("invalid log level %s, valid values are: debug, info, warn, error", s)
+	}
+	return l, nil
+}
+
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+func Level() slog.Level {
+	return level.Level()
+}
+
+func SetContent(enabled bool) {
+	content.Store(enabled)
+}
+
+func ContentEnabled() bool {
+	return content.Load()
+}
+
+// Content describes clipboard content by its size and hash. The content itself
+// is only included when content logging is enabled and the level is debug.
+func Content(key string, b []byte) slog.Attr {
+	sum := sha256.Sum256(b)
+	attrs := []any{
+		slog.Int("size", len(b)),
+		slog.String("sha256", hex.EncodeToString(sum[:8])),
+	}
+	if content.Load() && level.Level() <= slog.LevelDebug {
+		attrs = append(attrs, slog.String("content", string(b)))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// LevelName returns the lowercase name used in the configuration
+func LevelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContent_is_redacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, Setup(buf, Config{Level: "debug", Format: FormatJSON}))
+
+	slog.Debug("No match found", Content("line", []byte("secret password")))
+	assert.NotContains(t, buf.String(), "secret password")
+	assert.Contains(t, buf.String(), `"size":15`)
+	assert.Contains(t, buf.String(), `"sha256":`)
+}
+
+func TestContent_is_logged_when_enabled_in_debug(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, Setup(buf, Config{Level: "debug", Content: true}))
+
+	slog.Debug("No match found", Content("line", []byte("secret password")))
+	assert.Contains(t, buf.String(), "secret password")
+
+	buf.Reset()
+	SetLevel(slog.LevelInfo)
+	slog.Info("No match found", Content("line", []byte("secret password")))
+	assert.NotContains(t, buf.String(), "secret password")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, Config{}.Validate())
+	require.NoError(t, Config{Level: "warn", Format: FormatJSON}.Validate())
+	require.Error(t, Config{Level: "verbose"}.Validate())
+	require.Error(t, Config{Format: "xml"}.Validate())
+}
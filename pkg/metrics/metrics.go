@@ -0,0 +1,243 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// DefaultBuckets are histogram buckets in seconds suited for process executions
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metrics and renders them in the Prometheus text format
+type Registry struct {
+	lock    sync.Mutex
+	metrics []*metric
+	onWrite []func()
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+type series struct {
+	labels  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type metric struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+
+	lock   sync.Mutex
+	series map[string]*series
+}
+
+func (r *Registry) register(name, help string, typ metricType, buckets []float64, labels []string) *metric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	m := &metric{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+	r.metrics = append(r.metrics, m)
+	return m
+}
+
+// OnWrite registers a function called before the metrics are rendered,
+// useful to refresh gauges that are expensive to keep up to date.
+func (r *Registry) OnWrite(fn func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.onWrite = append(r.onWrite, fn)
+}
+
+// with returns the series for the label values, the metric lock must be held
+func (m *metric) with(values []string) *series {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metric %s expects %d labels, got %d", m.name, len(m.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{
+			labels:  slices.Clone(values),
+			buckets: make([]uint64, len(m.buckets)),
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+func (m *metric) reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.series = map[string]*series{}
+}
+
+type Counter struct{ m *metric }
+
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{m: r.register(name, help, typeCounter, nil, labels)}
+}
+
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *Counter) Add(v float64, labels ...string) {
+	c.m.lock.Lock()
+	defer c.m.lock.Unlock()
+	c.m.with(labels).value += v
+}
+
+type Gauge struct{ m *metric }
+
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{m: r.register(name, help, typeGauge, nil, labels)}
+}
+
+func (g *Gauge) Set(v float64, labels ...string) {
+	g.m.lock.Lock()
+	defer g.m.lock.Unlock()
+	g.m.with(labels).value = v
+}
+
+// Reset removes every series, so label values that disappeared are not reported anymore
+func (g *Gauge) Reset() {
+	g.m.reset()
+}
+
+type Histogram struct{ m *metric }
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{m: r.register(name, help, typeHistogram, buckets, labels)}
+}
+
+func (h *Histogram) Observe(v float64, labels ...string) {
+	h.m.lock.Lock()
+	defer h.m.lock.Unlock()
+	s := h.m.with(labels)
+	for i, upper := range h.m.buckets {
+		if v <= upper {
+			s.buckets[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// WriteTo renders every metric in the Prometheus text exposition format
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	r.lock.Lock()
+	onWrite := slices.Clone(r.onWrite)
+	metrics := slices.Clone(r.metrics)
+	r.lock.Unlock()
+
+	for _, fn := range onWrite {
+		fn()
+	}
+
+	w := &countingWriter{w: bufio.NewWriter(out)}
+	for _, m := range metrics {
+		m.write(w)
+	}
+	if w.err != nil {
+		return w.n, w.err
+	}
+	return w.n, w.w.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(resp)
+	})
+}
+
+func (m *metric) write(w *countingWriter) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	w.printf("# HELP %s %s\n", m.name, m.help)
+	w.printf("# TYPE %s %s\n", m.name, m.typ)
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		s := m.series[key]
+		if m.typ != typeHistogram {
+			w.printf("%s%s %s\n", m.name, formatLabels(m.labels, s.labels), formatValue(s.value))
+			continue
+		}
+		for i, upper := range m.buckets {
+			w.printf("%s_bucket%s %d\n", m.name, formatLabels(append(slices.Clone(m.labels), "le"), append(slices.Clone(s.labels), formatValue(upper))), s.buckets[i])
+		}
+		w.printf("%s_bucket%s %d\n", m.name, formatLabels(append(slices.Clone(m.labels), "le"), append(slices.Clone(s.labels), "+Inf")), s.count)
+		w.printf("%s_sum%s %s\n", m.name, formatLabels(m.labels, s.labels), formatValue(s.sum))
+		w.printf("%s_count%s %d\n", m.name, formatLabels(m.labels, s.labels), s.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(values[i]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) printf(format string, args ...any) {
+	if c.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(c.w, format, args...)
+	c.n += int64(n)
+	c.err = err
+}
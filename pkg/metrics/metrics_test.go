@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	r := NewRegistry()
+	captures := r.Counter("captures_total", "Captured selections", "selection")
+	size := r.Gauge("history_bytes", "History size")
+	latency := r.Histogram("latency_seconds", "Latency", []float64{0.1, 1}, "mode")
+
+	captures.Inc("primary")
+	captures.Inc("primary")
+	captures.Inc(`cli"p`)
+	latency.Observe(0.5, "paste")
+	r.OnWrite(func() {
+		size.Set(1024)
+	})
+
+	buf := &bytes.Buffer{}
+	_, err := r.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, `# HELP captures_total Captured selections
+# TYPE captures_total counter
+captures_total{selection="cli\"p"} 1
+captures_total{selection="primary"} 2
+# HELP history_bytes History size
+# TYPE history_bytes gauge
+history_bytes 1024
+# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{mode="paste",le="0.1"} 0
+latency_seconds_bucket{mode="paste",le="1"} 1
+latency_seconds_bucket{mode="paste",le="+Inf"} 1
+latency_seconds_sum{mode="paste"} 0.5
+latency_seconds_count{mode="paste"} 1
+`, buf.String())
+}
@@ -0,0 +1,159 @@
+package picker
+
+import (
+	"blueclip/pkg/fuzzy"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"sort"
+	"unicode/utf8"
+)
+
+// Item is an entry of the history as shown by the picker
+type Item struct {
+	ID       string
+	Category selections.SelectionRetentionType
+	Target   xclip.ValidTarget
+	// Register is the name of the register holding the item, empty for the history
+	Register string
+	Label    string
+	Line     string
+}
+
+// Text is the text shown and matched for the item, the line prefixed by the label if any
+func (i Item) Text() string {
+	if i.Label == "" {
+		return i.Line
+	}
+	return "[" + i.Label + "] " + i.Line
+}
+
+type match struct {
+	Item
+	score     int
+	positions []int
+}
+
+// Model holds the picker state independently of the terminal
+type Model struct {
+	items    []Item
+	query    string
+	hidden   map[selections.SelectionRetentionType]bool
+	filtered []match
+	cursor   int
+	offset   int
+}
+
+func NewModel() *Model {
+	return &Model{
+		hidden: map[selections.SelectionRetentionType]bool{},
+	}
+}
+
+// SetItems replaces the items keeping the cursor on the same item if it still exists
+func (m *Model) SetItems(items []Item) {
+	selected, ok := m.Selected()
+	previous := m.cursor
+	m.items = items
+	m.filter()
+	if !ok {
+		return
+	}
+	for i, match := range m.filtered {
+		if match.ID == selected.ID {
+			m.cursor = i
+			return
+		}
+	}
+	// The item is gone, stay at the same position
+	m.cursor = previous
+	m.Move(0)
+}
+
+func (m *Model) Query() string {
+	return m.query
+}
+
+func (m *Model) Type(r rune) {
+	m.query += string(r)
+	m.filter()
+}
+
+func (m *Model) Backspace() {
+	if m.query == "" {
+		return
+	}
+	_, size := utf8.DecodeLastRuneInString(m.query)
+	m.query = m.query[:len(m.query)-size]
+	m.filter()
+}
+
+func (m *Model) ClearQuery() {
+	m.query = ""
+	m.filter()
+}
+
+// Toggle shows or hides the items of a category
+func (m *Model) Toggle(category selections.SelectionRetentionType) {
+	m.hidden[category] = !m.hidden[category]
+	m.filter()
+}
+
+func (m *Model) Hidden(category selections.SelectionRetentionType) bool {
+	return m.hidden[category]
+}
+
+// Move moves the cursor by delta items, clamped to the list
+func (m *Model) Move(delta int) {
+	m.cursor = max(0, min(len(m.filtered)-1, m.cursor+delta))
+}
+
+func (m *Model) Selected() (Item, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return Item{}, false
+	}
+	return m.filtered[m.cursor].Item, true
+}
+
+// Counts returns the number of matching items and the total
+func (m *Model) Counts() (int, int) {
+	return len(m.filtered), len(m.items)
+}
+
+// Visible returns the matches that fit in height rows, scrolling to keep the
+// cursor visible, and the index of the cursor among them
+func (m *Model) Visible(height int) ([]match, int) {
+	if height <= 0 {
+		return nil, -1
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.filtered)-height))
+	end := min(len(m.filtered), m.offset+height)
+	return m.filtered[m.offset:end], m.cursor - m.offset
+}
+
+func (m *Model) filter() {
+	m.filtered = m.filtered[:0]
+	for _, item := range m.items {
+		if m.hidden[item.Category] {
+			continue
+		}
+		score, positions, ok := fuzzy.Match(m.query, item.Text())
+		if !ok {
+			continue
+		}
+		m.filtered = append(m.filtered, match{Item: item, score: score, positions: positions})
+	}
+	if m.query != "" {
+		// Stable so equally good matches keep the list order
+		sort.SliceStable(m.filtered, func(i, j int) bool {
+			return m.filtered[i].score > m.filtered[j].score
+		})
+	}
+	m.cursor = 0
+	m.offset = 0
+}
@@ -0,0 +1,121 @@
+package picker
+
+import (
+	"blueclip/pkg/selections"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func items() []Item {
+	return []Item{
+		{ID: "1", Category: selections.SelectionRetentionTypeEphemeral, Line: "docker run --rm alpine"},
+		{ID: "2", Category: selections.SelectionRetentionTypeImportant, Line: "git commit --amend"},
+		{ID: "3", Category: selections.SelectionRetentionTypeEphemeral, Line: "go test ./..."},
+	}
+}
+
+func TestModel_filter(t *testing.T) {
+	m := NewModel()
+	m.SetItems(items())
+
+	matched, total := m.Counts()
+	assert.Equal(t, 3, matched)
+	assert.Equal(t, 3, total)
+
+	for _, r := range "tes" {
+		m.Type(r)
+	}
+	visible, _ := m.Visible(10)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "3", visible[0].ID)
+
+	m.Backspace()
+	visible, _ = m.Visible(10)
+	require.Len(t, visible, 2)
+	// better matches rank first
+	assert.Equal(t, "3", visible[0].ID)
+	assert.Equal(t, "2", visible[1].ID)
+
+	m.ClearQuery()
+	m.Toggle(selections.SelectionRetentionTypeEphemeral)
+	visible, _ = m.Visible(10)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "2", visible[0].ID)
+	assert.True(t, m.Hidden(selections.SelectionRetentionTypeEphemeral))
+}
+
+func TestModel_toggle_pinned(t *testing.T) {
+	m := NewModel()
+	m.SetItems(append([]Item{{ID: "0", Category: selections.SelectionRetentionTypePinned, Line: "pinned"}}, items()...))
+
+	m.Toggle(selections.SelectionRetentionTypePinned)
+	visible, _ := m.Visible(10)
+	require.Len(t, visible, 3)
+	assert.NotContains(t, []string{visible[0].ID, visible[1].ID, visible[2].ID}, "0")
+
+	// Only the pinned selections are shown with the other categories hidden
+	m.Toggle(selections.SelectionRetentionTypePinned)
+	m.Toggle(selections.SelectionRetentionTypeEphemeral)
+	m.Toggle(selections.SelectionRetentionTypeImportant)
+	visible, _ = m.Visible(10)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "0", visible[0].ID)
+}
+
+func TestModel_cursor(t *testing.T) {
+	m := NewModel()
+	m.SetItems(items())
+
+	m.Move(1)
+	selected, ok := m.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "2", selected.ID)
+
+	// the cursor follows the item when the list is reloaded
+	m.SetItems(append([]Item{{ID: "0", Line: "new"}}, items()...))
+	selected, _ = m.Selected()
+	assert.Equal(t, "2", selected.ID)
+
+	// and stays at the same position when the item is gone
+	m.SetItems([]Item{items()[0], items()[2]})
+	selected, _ = m.Selected()
+	assert.Equal(t, "3", selected.ID)
+
+	m.Move(10)
+	visible, cursor := m.Visible(1)
+	require.Len(t, visible, 1)
+	assert.Equal(t, 0, cursor)
+	assert.Equal(t, "3", visible[0].ID)
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("a\x1b[Añ\x7f\r\x1b[1;5C\x13\x06\x1b"))
+	assert.Equal(t, []keyPress{
+		{key: keyRune, rune: 'a'},
+		{key: keyUp},
+		{key: keyRune, rune: 'ñ'},
+		{key: keyBackspace},
+		{key: keyEnter},
+		{key: keyCtrlS},
+		{key: keyCtrlF},
+		{key: keyEscape},
+	}, keys)
+}
+
+func TestModel_filter_label(t *testing.T) {
+	m := NewModel()
+	labeled := items()
+	labeled[1].Label = "prod cleanup"
+	m.SetItems(labeled)
+
+	for _, r := range "prod" {
+		m.Type(r)
+	}
+	visible, _ := m.Visible(10)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "2", visible[0].ID)
+	assert.Equal(t, "[prod cleanup] git commit --amend", visible[0].Text())
+	assert.Equal(t, []int{1, 2, 3, 4}, visible[0].positions)
+}
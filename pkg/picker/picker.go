@@ -0,0 +1,486 @@
+package picker
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Backend performs the actions requested from the picker
+type Backend interface {
+	Items(ctx context.Context) ([]Item, error)
+	// Preview renders the item to fit in the given dimensions
+	Preview(ctx context.Context, id string, width, height int) (string, error)
+	Copy(ctx context.Context, id string, clipboard xclip.ClipboardSelection) error
+	Promote(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) error
+}
+
+const (
+	escape           = "\x1b"
+	enterAltScreen   = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen   = "\x1b[?25h\x1b[?1049l"
+	clearLine        = "\x1b[K"
+	reset            = "\x1b[0m"
+	reverse          = "\x1b[7m"
+	highlight        = "\x1b[1;33m"
+	resetHighlight   = "\x1b[22;39m"
+	dim              = "\x1b[2m"
+	separator        = "│"
+	minimumListWidth = 20
+)
+
+type key int
+
+const (
+	keyRune key = iota
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyDelete
+	keyCtrlC
+	keyCtrlD
+	keyCtrlE
+	keyCtrlF
+	keyCtrlN
+	keyCtrlO
+	keyCtrlP
+	keyCtrlS
+	keyCtrlT
+	keyCtrlU
+)
+
+type keyPress struct {
+	key  key
+	rune rune
+}
+
+type picker struct {
+	backend Backend
+	model   *Model
+	tty     *os.File
+
+	width, height int
+	status        string
+	previews      map[string]string
+}
+
+// Run opens a full screen picker on the terminal until an item is copied or the user quits
+func Run(ctx context.Context, backend Backend) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open terminal: %v", err)
+	}
+	defer tty.Close()
+
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set terminal in raw mode: %v", err)
+	}
+	defer term.Restore(int(tty.Fd()), state)
+
+	io.WriteString(tty, enterAltScreen)
+	defer io.WriteString(tty, leaveAltScreen)
+
+	p := &picker{
+		backend:  backend,
+		model:    NewModel(),
+		tty:      tty,
+		previews: map[string]string{},
+	}
+	if err := p.refresh(ctx); err != nil {
+		return err
+	}
+	return p.loop(ctx)
+}
+
+func (p *picker) refresh(ctx context.Context) error {
+	items, err := p.backend.Items(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list selections: %v", err)
+	}
+	p.model.SetItems(items)
+	p.previews = map[string]string{}
+	return nil
+}
+
+func (p *picker) loop(ctx context.Context) error {
+	input := make(chan []byte)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := p.tty.Read(buf)
+			if err != nil {
+				close(input)
+				return
+			}
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			input <- chunk
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+	defer signal.Stop(resize)
+
+	p.draw(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resize:
+		case chunk, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(chunk) {
+				done, err := p.handle(ctx, k)
+				if done || err != nil {
+					return err
+				}
+			}
+		}
+		p.draw(ctx)
+	}
+}
+
+// handle applies a key press, it returns true when the picker must exit
+func (p *picker) handle(ctx context.Context, k keyPress) (bool, error) {
+	p.status = ""
+	switch k.key {
+	case keyRune:
+		p.model.Type(k.rune)
+	case keyBackspace:
+		p.model.Backspace()
+	case keyCtrlU:
+		p.model.ClearQuery()
+	case keyUp, keyCtrlP:
+		p.model.Move(-1)
+	case keyDown, keyCtrlN:
+		p.model.Move(1)
+	case keyPageUp:
+		p.model.Move(-p.bodyHeight())
+	case keyPageDown:
+		p.model.Move(p.bodyHeight())
+	case keyEscape, keyCtrlC:
+		return true, nil
+	case keyEnter, keyCtrlO:
+		item, ok := p.model.Selected()
+		if !ok {
+			return false, nil
+		}
+		clipboard := xclip.ClipboardSelectionClipboard
+		if k.key == keyCtrlO {
+			clipboard = xclip.ClipboardSelectionPrimary
+		}
+		if err := p.backend.Copy(ctx, item.ID, clipboard); err != nil {
+			p.status = err.Error()
+			return false, nil
+		}
+		return true, nil
+	case keyCtrlS:
+		p.act(ctx, "promoted", p.backend.Promote)
+	case keyCtrlD, keyDelete:
+		p.act(ctx, "deleted", p.backend.Delete)
+	case keyCtrlE:
+		p.model.Toggle(selections.SelectionRetentionTypeEphemeral)
+	case keyCtrlT:
+		p.model.Toggle(selections.SelectionRetentionTypeImportant)
+	case keyCtrlF:
+		p.model.Toggle(selections.SelectionRetentionTypePinned)
+	}
+	return false, nil
+}
+
+// act runs an action on the selected item and reloads the list
+func (p *picker) act(ctx context.Context, done string, action func(context.Context, string) error) {
+	item, ok := p.model.Selected()
+	if !ok {
+		return
+	}
+	if err := action(ctx, item.ID); err != nil {
+		p.status = err.Error()
+		return
+	}
+	if err := p.refresh(ctx); err != nil {
+		p.status = err.Error()
+		return
+	}
+	p.status = done
+}
+
+func (p *picker) bodyHeight() int {
+	return max(1, p.height-2)
+}
+
+func (p *picker) draw(ctx context.Context) {
+	width, height, err := term.GetSize(int(p.tty.Fd()))
+	if err == nil {
+		p.width, p.height = width, height
+	}
+
+	listWidth := max(minimumListWidth, p.width*2/5)
+	previewWidth := max(0, p.width-listWidth-1)
+	body := p.bodyHeight()
+
+	frame := &strings.Builder{}
+	frame.WriteString("\x1b[H")
+
+	matched, total := p.model.Counts()
+	counts := fmt.Sprintf("%d/%d", matched, total)
+	prompt := truncate("> "+p.model.Query(), p.width-len(counts)-1)
+	frame.WriteString(prompt)
+	frame.WriteString(strings.Repeat(" ", max(1, p.width-utf8.RuneCountInString(prompt)-len(counts))))
+	frame.WriteString(dim + counts + reset + clearLine + "\r\n")
+
+	visible, cursor := p.model.Visible(body)
+	var preview []string
+	if item, ok := p.model.Selected(); ok && previewWidth > 0 {
+		preview = strings.Split(p.preview(ctx, item.ID, previewWidth, body), "\n")
+	}
+
+	for row := 0; row < body; row++ {
+		if row < len(visible) {
+			frame.WriteString(renderItem(visible[row], listWidth, row == cursor))
+		} else {
+			frame.WriteString(strings.Repeat(" ", listWidth))
+		}
+		if previewWidth > 0 {
+			frame.WriteString(dim + separator + reset)
+			if row < len(preview) {
+				frame.WriteString(renderPreviewLine(preview[row], previewWidth))
+			}
+		}
+		frame.WriteString(reset + clearLine + "\r\n")
+	}
+
+	footer := p.status
+	if footer == "" {
+		footer = fmt.Sprintf("enter copy  ^O primary  ^S promote  ^D delete  ^E ephemeral %s  ^T important %s  ^F pinned %s  esc quit",
+			onOff(!p.model.Hidden(selections.SelectionRetentionTypeEphemeral)),
+			onOff(!p.model.Hidden(selections.SelectionRetentionTypeImportant)),
+			onOff(!p.model.Hidden(selections.SelectionRetentionTypePinned)),
+		)
+	}
+	frame.WriteString(dim + truncate(footer, p.width) + reset + clearLine)
+
+	io.WriteString(p.tty, frame.String())
+}
+
+func (p *picker) preview(ctx context.Context, id string, width, height int) string {
+	cacheKey := fmt.Sprintf("%s:%dx%d", id, width, height)
+	if preview, ok := p.previews[cacheKey]; ok {
+		return preview
+	}
+	preview, err := p.backend.Preview(ctx, id, width, height)
+	if err != nil {
+		preview = err.Error()
+	}
+	p.previews[cacheKey] = preview
+	return preview
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+func categoryMarker(category selections.SelectionRetentionType) string {
+	switch category {
+	case selections.SelectionRetentionTypeImportant:
+		return "I"
+	case selections.SelectionRetentionTypeEphemeral:
+		return "E"
+	case selections.SelectionRetentionTypePinned:
+		return "P"
+	default:
+		return "L"
+	}
+}
+
+// renderItem renders a list row highlighting the runes that matched the query
+func renderItem(m match, width int, selected bool) string {
+	out := &strings.Builder{}
+	if selected {
+		out.WriteString(reverse)
+	}
+	if m.Register != "" {
+		out.WriteString(m.Register + " ")
+	} else {
+		out.WriteString(categoryMarker(m.Category) + " ")
+	}
+
+	positions := map[int]bool{}
+	for _, p := range m.positions {
+		positions[p] = true
+	}
+
+	used := 2
+	for offset, r := range m.Text() {
+		if used >= width {
+			break
+		}
+		r = printable(r)
+		if r == 0 {
+			continue
+		}
+		if positions[offset] {
+			out.WriteString(highlight + string(r) + resetHighlight)
+		} else {
+			out.WriteRune(r)
+		}
+		used++
+	}
+	out.WriteString(strings.Repeat(" ", width-used))
+	out.WriteString(reset)
+	return out.String()
+}
+
+// renderPreviewLine fits a preview line in the pane. Lines with escape sequences
+// come from the image preview, which is already rendered to the pane width.
+func renderPreviewLine(line string, width int) string {
+	if strings.Contains(line, escape) {
+		return line
+	}
+	line = strings.ReplaceAll(line, "\t", "    ")
+	out := &strings.Builder{}
+	used := 0
+	for _, r := range line {
+		if used >= width {
+			break
+		}
+		if unicode.IsControl(r) {
+			continue
+		}
+		out.WriteRune(r)
+		used++
+	}
+	return out.String()
+}
+
+// printable replaces runes that would break a single line row
+func printable(r rune) rune {
+	switch {
+	case r == '\n':
+		return '↵'
+	case r == '\t':
+		return ' '
+	case unicode.IsControl(r):
+		return 0
+	}
+	return r
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+func parseKeys(b []byte) []keyPress {
+	keys := []keyPress{}
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			switch {
+			case len(b) == 1:
+				keys = append(keys, keyPress{key: keyEscape})
+				b = b[1:]
+				continue
+			case hasPrefix(b, "\x1b[A"), hasPrefix(b, "\x1bOA"):
+				keys = append(keys, keyPress{key: keyUp})
+				b = b[3:]
+				continue
+			case hasPrefix(b, "\x1b[B"), hasPrefix(b, "\x1bOB"):
+				keys = append(keys, keyPress{key: keyDown})
+				b = b[3:]
+				continue
+			case hasPrefix(b, "\x1b[5~"):
+				keys = append(keys, keyPress{key: keyPageUp})
+				b = b[4:]
+				continue
+			case hasPrefix(b, "\x1b[6~"):
+				keys = append(keys, keyPress{key: keyPageDown})
+				b = b[4:]
+				continue
+			case hasPrefix(b, "\x1b[3~"):
+				keys = append(keys, keyPress{key: keyDelete})
+				b = b[4:]
+				continue
+			}
+			// Unknown sequence, skip it up to its final byte
+			end := 2
+			if b[1] == '[' || b[1] == 'O' {
+				for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+					end++
+				}
+				end++
+			}
+			b = b[min(len(b), end):]
+			continue
+		}
+
+		var k key
+		switch b[0] {
+		case '\r', '\n':
+			k = keyEnter
+		case 0x7f, 0x08:
+			k = keyBackspace
+		case 0x03:
+			k = keyCtrlC
+		case 0x04:
+			k = keyCtrlD
+		case 0x05:
+			k = keyCtrlE
+		case 0x06:
+			k = keyCtrlF
+		case 0x0e:
+			k = keyCtrlN
+		case 0x0f:
+			k = keyCtrlO
+		case 0x10:
+			k = keyCtrlP
+		case 0x13:
+			k = keyCtrlS
+		case 0x14:
+			k = keyCtrlT
+		case 0x15:
+			k = keyCtrlU
+		default:
+			r, size := utf8.DecodeRune(b)
+			b = b[size:]
+			if !unicode.IsControl(r) && r != utf8.RuneError {
+				keys = append(keys, keyPress{key: keyRune, rune: r})
+			}
+			continue
+		}
+		keys = append(keys, keyPress{key: k})
+		b = b[1:]
+	}
+	return keys
+}
+
+func hasPrefix(b []byte, prefix string) bool {
+	return len(b) >= len(prefix) && string(b[:len(prefix)]) == prefix
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"blueclip/pkg/fuzzy"
+	"fmt"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mode is the way a query is matched against the content
+type Mode string
+
+const (
+	// ModeSubstring finds the query as is, with smart case
+	ModeSubstring Mode = "substring"
+	// ModeRegex finds a regular expression
+	ModeRegex Mode = "regex"
+	// ModeFuzzy finds the runes of the query in order, as the picker does
+	ModeFuzzy Mode = "fuzzy"
+)
+
+// Modes are the available modes, the first one is the default
+var Modes = []Mode{ModeSubstring, ModeRegex, ModeFuzzy}
+
+// ParseMode validates the mode name, empty is the default mode
+func ParseMode(value string) (Mode, error) {
+	if value == "" {
+		return ModeSubstring, nil
+	}
+	for _, mode := range Modes {
+		if Mode(value) == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid mode %s, allowed modes are: %v", value, Modes)
+}
+
+// Span is the byte range [start, end) of a match in the text
+type Span [2]int
+
+// Result is how well a text matches the query
+type Result struct {
+	// Score is higher for better matches, it only compares results of the same query
+	Score int
+	// Spans are the matched parts of the text, in order
+	Spans []Span
+}
+
+// Matcher matches texts against a query
+type Matcher struct {
+	mode  Mode
+	query string
+	re    *regexp.Regexp
+}
+
+// New compiles the query for the mode. An empty query matches everything.
+func New(mode Mode, query string) (*Matcher, error) {
+	m := &Matcher{mode: mode, query: query}
+	switch mode {
+	case ModeSubstring:
+		pattern := regexp.QuoteMeta(query)
+		if !hasUpper(query) {
+			pattern = "(?i)" + pattern
+		}
+		m.re = regexp.MustCompile(pattern)
+	case ModeRegex:
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		m.re = re
+	case ModeFuzzy:
+	default:
+		return nil, fmt.Errorf("invalid mode %s, allowed modes are: %v", mode, Modes)
+	}
+	return m, nil
+}
+
+// Match reports whether the text matches and how well
+func (m *Matcher) Match(text string) (Result, bool) {
+	if m.query == "" {
+		return Result{}, true
+	}
+	if m.mode == ModeFuzzy {
+		score, positions, ok := fuzzy.Match(m.query, text)
+		if !ok {
+			return Result{}, false
+		}
+		return Result{Score: score, Spans: runeSpans(text, positions)}, true
+	}
+
+	matches := m.re.FindAllStringIndex(text, -1)
+	spans := make([]Span, 0, len(matches))
+	for _, match := range matches {
+		// Empty matches can't be highlighted
+		if match[0] < match[1] {
+			spans = append(spans, Span{match[0], match[1]})
+		}
+	}
+	if len(matches) == 0 {
+		return Result{}, false
+	}
+	// More matches rank first, then the earliest match
+	score := len(matches)*1000 - min(matches[0][0], 999)
+	return Result{Score: score, Spans: spans}, true
+}
+
+// runeSpans merges the positions of consecutive runes into spans
+func runeSpans(text string, positions []int) []Span {
+	spans := []Span{}
+	for _, p := range positions {
+		_, size := utf8.DecodeRuneInString(text[p:])
+		if n := len(spans); n > 0 && spans[n-1][1] == p {
+			spans[n-1][1] = p + size
+			continue
+		}
+		spans = append(spans, Span{p, p + size})
+	}
+	return spans
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
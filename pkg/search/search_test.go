@@ -0,0 +1,54 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  Mode
+		query string
+		text  string
+		ok    bool
+		spans []Span
+	}{
+		{name: "empty query matches everything", mode: ModeSubstring, query: "", text: "anything", ok: true},
+		{name: "substring every occurrence", mode: ModeSubstring, query: "go", text: "go to Go\ngo", ok: true, spans: []Span{{0, 2}, {6, 8}, {9, 11}}},
+		{name: "substring smart case", mode: ModeSubstring, query: "Go", text: "go to Go", ok: true, spans: []Span{{6, 8}}},
+		{name: "substring is literal", mode: ModeSubstring, query: "a.c", text: "abc", ok: false},
+		{name: "regex past the first line", mode: ModeRegex, query: `\d+`, text: "none\nport 8080", ok: true, spans: []Span{{10, 14}}},
+		{name: "fuzzy merges consecutive runes", mode: ModeFuzzy, query: "dockrun", text: "docker run", ok: true, spans: []Span{{0, 4}, {5, 6}, {8, 10}}},
+		{name: "fuzzy multibyte runes", mode: ModeFuzzy, query: "ñu", text: "año ñu", ok: true, spans: []Span{{5, 8}}},
+		{name: "fuzzy no match", mode: ModeFuzzy, query: "xyz", text: "docker run", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.mode, tt.query)
+			require.NoError(t, err)
+			result, ok := m.Match(tt.text)
+			require.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.spans, result.Spans)
+		})
+	}
+}
+
+func TestMatcher_Match_score(t *testing.T) {
+	m, err := New(ModeSubstring, "key")
+	require.NoError(t, err)
+	twice, _ := m.Match("key and key")
+	once, _ := m.Match("key")
+	late, _ := m.Match("the key")
+	assert.Greater(t, twice.Score, once.Score)
+	assert.Greater(t, once.Score, late.Score)
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, err := New(ModeRegex, "(")
+	assert.Error(t, err)
+	_, err = ParseMode("glob")
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package selections
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/logging"
+	"blueclip/pkg/xclip"
+	"log/slog"
+	"slices"
+)
+
+// Budget limits the bytes used by the stored selections, zero values are unlimited.
+// The oldest ephemeral selections are dropped first, then the oldest important ones.
+// Pinned and collected selections count towards the budgets but are never dropped.
+type Budget struct {
+	Ephemeral bytesize.Size `json:"ephemeral,omitempty"`
+	Important bytesize.Size `json:"important,omitempty"`
+	Total     bytesize.Size `json:"total,omitempty"`
+	// Targets limits the bytes used by each target, such as image/png
+	Targets map[xclip.ValidTarget]bytesize.Size `json:"targets,omitempty"`
+}
+
+// enforceBudget drops the oldest selections over the budget and returns them, the caller must hold the lock
+func (s *Set) enforceBudget() []Eviction {
+	budget := s.Options.Budget
+	all := func(Selection) bool { return true }
+
+	evicted := []Eviction{}
+	evicted = append(evicted, s.evictOver("ephemeral", budget.Ephemeral, []SelectionRetentionType{SelectionRetentionTypeEphemeral}, all)...)
+	evicted = append(evicted, s.evictOver("important", budget.Important, []SelectionRetentionType{SelectionRetentionTypeImportant}, all)...)
+
+	targets := []xclip.ValidTarget{}
+	for target := range budget.Targets {
+		targets = append(targets, target)
+	}
+	slices.Sort(targets)
+	for _, target := range targets {
+		evicted = append(evicted, s.evictOver(string(target), budget.Targets[target], categories, func(sel Selection) bool {
+			return sel.Target == target
+		})...)
+	}
+
+	return append(evicted, s.evictOver("total", budget.Total, categories, all)...)
+}
+
+// evictOver drops the oldest matching ephemeral and then important selections until
+// the matching selections in the counted categories fit in the limit
+func (s *Set) evictOver(budget string, limit bytesize.Size, counted []SelectionRetentionType, match func(Selection) bool) []Eviction {
+	evicted := []Eviction{}
+	if limit <= 0 {
+		return evicted
+	}
+
+	used := bytesize.Size(0)
+	for _, typ := range counted {
+		for _, sel := range *s.list(typ) {
+			if match(sel) {
+				used += bytesize.Size(len(sel.Content))
+			}
+		}
+	}
+
+	for _, typ := range []SelectionRetentionType{SelectionRetentionTypeEphemeral, SelectionRetentionTypeImportant} {
+		if used <= limit {
+			break
+		}
+		if !slices.Contains(counted, typ) {
+			continue
+		}
+		list := s.list(typ)
+		kept := []Selection{}
+		for _, sel := range *list {
+			if used > limit && match(sel) && !sel.Collected() {
+				used -= bytesize.Size(len(sel.Content))
+				slog.Info("Dropping selection over budget", "budget", budget, "limit", limit, "category", typ, logging.Content("selection", sel.Content))
+				evicted = append(evicted, Eviction{Entry: Entry{Selection: sel, Category: typ}, Reason: budget + " budget"})
+				continue
+			}
+			kept = append(kept, sel)
+		}
+		*list = kept
+	}
+	return evicted
+}
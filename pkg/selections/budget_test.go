@@ -0,0 +1,67 @@
+package selections
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_budget(t *testing.T) {
+	s := NewSelections()
+	add := func(content string, size int, target xclip.ValidTarget) {
+		_, err := s.Add(Selection{
+			Selection: xclip.Selection{
+				Content: append([]byte(content), bytes.Repeat([]byte{'.'}, size-len(content))...),
+				Target:  target,
+			},
+		})
+		require.NoError(t, err)
+	}
+	names := func(list []Selection) []string {
+		result := []string{}
+		for _, sel := range list {
+			result = append(result, string(bytes.TrimRight(sel.Content, ".")))
+		}
+		return result
+	}
+
+	add("pinned", 40, xclip.ValidTargetImagePng)
+	_, err := s.Pin(s.Entries()[0].ID())
+	require.NoError(t, err)
+	add("image1", 30, xclip.ValidTargetImagePng)
+	add("text1", 10, xclip.ValidTargetUTF8_STRING)
+	add("image2", 30, xclip.ValidTargetImagePng)
+	_, err = s.Promote(s.Entries()[0].ID())
+	require.NoError(t, err)
+	add("text2", 10, xclip.ValidTargetUTF8_STRING)
+	add("text3", 10, xclip.ValidTargetUTF8_STRING)
+
+	assert.Equal(t, Usage{Count: 6, Bytes: 130}, s.Stats().Total)
+
+	// Lowering the limits applies them right away
+	options := DefaultOptions()
+	options.Budget.Ephemeral = 25
+	s.SetOptions(options)
+	assert.Equal(t, []string{"text2", "text3"}, names(s.Ephemeral))
+
+	// Pinned images count towards the target budget but only the others are dropped
+	options.Budget.Targets = map[xclip.ValidTarget]bytesize.Size{xclip.ValidTargetImagePng: 50}
+	s.SetOptions(options)
+	assert.Equal(t, []string{}, names(s.Important))
+	assert.Equal(t, []string{"pinned"}, names(s.Pinned))
+
+	options.Budget.Total = 55
+	s.SetOptions(options)
+	assert.Equal(t, []string{"text3"}, names(s.Ephemeral))
+
+	add("text4", 10, xclip.ValidTargetUTF8_STRING)
+	assert.Equal(t, []string{"text4"}, names(s.Ephemeral))
+
+	stats := s.Stats()
+	assert.Equal(t, Usage{Count: 2, Bytes: 50}, stats.Total)
+	assert.Equal(t, bytesize.Size(55), stats.Limits.Budget.Total)
+}
@@ -0,0 +1,153 @@
+package selections
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Order is a strategy to rank the entries that follow the last and pinned selections
+type Order string
+
+const (
+	// OrderInterleave interleaves important and ephemeral selections from the most recent
+	OrderInterleave Order = "interleave"
+	// OrderRecent sorts by the last time the selection was captured or copied
+	OrderRecent Order = "recent"
+	// OrderFrecency sorts by how often and how recently the selection was captured or copied
+	OrderFrecency Order = "frecency"
+	// OrderSize sorts by content size, the largest first
+	OrderSize Order = "size"
+)
+
+// Orders are the available strategies, the first one is the default
+var Orders = []Order{OrderInterleave, OrderRecent, OrderFrecency, OrderSize}
+
+// ParseOrder validates the order name, empty is the default order
+func ParseOrder(value string) (Order, error) {
+	if value == "" {
+		return OrderInterleave, nil
+	}
+	for _, order := range Orders {
+		if Order(value) == order {
+			return order, nil
+		}
+	}
+	return "", fmt.Errorf("invalid order %s, allowed orders are: %v", value, Orders)
+}
+
+const (
+	// FrecencyHalfLife is the time it takes for a use to count half
+	FrecencyHalfLife = 72 * time.Hour
+	// copyWeight makes a copy count more than a capture, copying is an explicit reuse
+	copyWeight = 3
+	// MaxUses is the number of captures and copies remembered per selection
+	MaxUses = 10
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// recordUse appends the time to the uses, keeping only the most recent ones. Zero times are ignored.
+func recordUse(uses []time.Time, at time.Time) []time.Time {
+	if at.IsZero() {
+		return uses
+	}
+	uses = append(uses, at)
+	if len(uses) > MaxUses {
+		uses = uses[len(uses)-MaxUses:]
+	}
+	return uses
+}
+
+// inherit keeps the history, tags and collections of a previous copy of the same content
+func (s *Selection) inherit(previous Selection) {
+	if s.Label == "" {
+		s.Label = previous.Label
+	}
+	s.Captures = previous.Captures
+	s.Copies = previous.Copies
+	s.Tags = previous.Tags
+	s.Collections = previous.Collections
+}
+
+// LastCopiedAt is the last time the selection was copied, zero if it never was
+func (s *Selection) LastCopiedAt() time.Time {
+	if len(s.Copies) == 0 {
+		return time.Time{}
+	}
+	return s.Copies[len(s.Copies)-1]
+}
+
+// LastUsedAt is the last time the selection was captured or copied
+func (s *Selection) LastUsedAt() time.Time {
+	last := s.CapturedAt
+	if copied := s.LastCopiedAt(); copied.After(last) {
+		last = copied
+	}
+	return last
+}
+
+// Frecency scores the selection by its captures and copies, every use decays exponentially with its age.
+// As all scores decay at the same rate, the ranking between selections does not change over time.
+func (s *Selection) Frecency(at time.Time) float64 {
+	score := 0.0
+	for _, t := range s.Captures {
+		score += decay(at.Sub(t))
+	}
+	for _, t := range s.Copies {
+		score += copyWeight * decay(at.Sub(t))
+	}
+	return score
+}
+
+func decay(age time.Duration) float64 {
+	return math.Exp2(-age.Hours() / FrecencyHalfLife.Hours())
+}
+
+// Ranked returns the entries in the given order. The last selection and the pinned selections
+// always come first, the order only ranks the rest. Entries that rank the same keep the interleave order.
+func (s *Set) Ranked(order Order, at time.Time) ([]Entry, error) {
+	order, err := ParseOrder(string(order))
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entries := s.entries()
+	if order == OrderInterleave {
+		return entries, nil
+	}
+
+	head := 0
+	if s.Last != nil {
+		head = 1
+	}
+	for head < len(entries) && entries[head].Category == SelectionRetentionTypePinned {
+		head++
+	}
+	rest := entries[head:]
+
+	var less func(a, b Entry) bool
+	switch order {
+	case OrderRecent:
+		less = func(a, b Entry) bool {
+			return a.LastUsedAt().After(b.LastUsedAt())
+		}
+	case OrderFrecency:
+		less = func(a, b Entry) bool {
+			return a.Frecency(at) > b.Frecency(at)
+		}
+	case OrderSize:
+		less = func(a, b Entry) bool {
+			return len(a.Content) > len(b.Content)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return less(rest[i], rest[j])
+	})
+	return entries, nil
+}
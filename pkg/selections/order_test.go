@@ -0,0 +1,123 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contents(entries []Entry) []string {
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, string(entry.Content))
+	}
+	return result
+}
+
+func TestSet_ranked(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	s := NewSelections()
+	add := func(content string) {
+		_, err := s.Add(Selection{
+			Selection:  xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING},
+			CapturedAt: clock,
+		})
+		require.NoError(t, err)
+		clock = clock.Add(time.Hour)
+	}
+	copyContent := func(content string) {
+		_, ok := s.Copy([]byte(content), CopyOptionKeepCategory(true))
+		require.True(t, ok)
+		clock = clock.Add(time.Hour)
+	}
+
+	add("snippet")
+	add("a much longer line")
+	add("x")
+	copyContent("snippet")
+	copyContent("snippet")
+	add("pinned")
+	_, err := s.Pin(s.Entries()[0].ID())
+	require.NoError(t, err)
+	add("last")
+
+	tests := []struct {
+		order Order
+		want  []string
+	}{
+		{order: "", want: []string{"last", "pinned", "snippet", "x", "a much longer line"}},
+		{order: OrderInterleave, want: []string{"last", "pinned", "snippet", "x", "a much longer line"}},
+		{order: OrderRecent, want: []string{"last", "pinned", "snippet", "x", "a much longer line"}},
+		{order: OrderFrecency, want: []string{"last", "pinned", "snippet", "x", "a much longer line"}},
+		{order: OrderSize, want: []string{"last", "pinned", "a much longer line", "snippet", "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			entries, err := s.Ranked(tt.order, clock)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, contents(entries))
+		})
+	}
+
+	// Recapturing older content makes it the most recent, but the copies keep the snippet first by frecency
+	add("a much longer line")
+	add("last")
+	entries, err := s.Ranked(OrderRecent, clock)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last", "pinned", "a much longer line", "snippet", "x"}, contents(entries))
+	entries, err = s.Ranked(OrderFrecency, clock)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last", "pinned", "snippet", "a much longer line", "x"}, contents(entries))
+
+	// The ranking does not change as time goes by
+	later, err := s.Ranked(OrderFrecency, clock.Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, contents(entries), contents(later))
+
+	// Selectors use the same order
+	index := 2
+	entry, err := s.Select(Selector{Index: &index, Order: OrderFrecency})
+	require.NoError(t, err)
+	assert.Equal(t, "snippet", string(entry.Content))
+
+	_, err = s.Ranked("random", clock)
+	assert.Error(t, err)
+}
+
+func TestSet_add_keeps_history(t *testing.T) {
+	s := NewSelections()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	add := func(content string, label string) {
+		_, err := s.Add(Selection{
+			Selection:  xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING},
+			CapturedAt: at,
+			Label:      label,
+		})
+		require.NoError(t, err)
+		at = at.Add(time.Minute)
+	}
+
+	add("a", "make build")
+	add("b", "")
+	add("a", "")
+
+	sel, ok := s.FindMatch([]byte("a"))
+	require.True(t, ok)
+	assert.Equal(t, "make build", sel.Label)
+	assert.Len(t, sel.Captures, 2)
+
+	for range MaxUses {
+		add("b", "")
+		add("a", "")
+	}
+	sel, ok = s.FindMatch([]byte("a"))
+	require.True(t, ok)
+	assert.Len(t, sel.Captures, MaxUses)
+}
@@ -0,0 +1,121 @@
+package selections
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policy moves selections between categories automatically. Zero values disable each rule.
+// Pinned selections are never touched.
+type Policy struct {
+	// PromoteAfterCopies promotes ephemeral selections copied this many times
+	PromoteAfterCopies int
+	// PromoteAfterCaptures promotes ephemeral selections captured this many times
+	PromoteAfterCaptures int
+	// DemoteAfter demotes important selections not captured or copied for this long.
+	// Only uses within this period count to promote, so demoted selections are not promoted right back.
+	DemoteAfter time.Duration
+	// ExpireAfter removes ephemeral selections not captured or copied for this long, collected selections are kept
+	ExpireAfter time.Duration
+}
+
+// Enabled reports whether any rule is set
+func (p Policy) Enabled() bool {
+	return p.PromoteAfterCopies > 0 || p.PromoteAfterCaptures > 0 || p.DemoteAfter > 0 || p.ExpireAfter > 0
+}
+
+// Transition is a change made by a policy, Entry has the category before the change
+type Transition struct {
+	Entry
+	// To is the new category, empty if the selection expired
+	To     SelectionRetentionType
+	Reason string
+}
+
+// ApplyPolicy evaluates the policy at the given time and returns the changes it made.
+// Selections without any recorded use are never demoted or expired.
+func (s *Set) ApplyPolicy(p Policy, at time.Time) []Transition {
+	if !p.Enabled() {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	transitions := []Transition{}
+	recent := func(uses []time.Time) int {
+		count := 0
+		for _, t := range uses {
+			if p.DemoteAfter <= 0 || at.Sub(t) < p.DemoteAfter {
+				count++
+			}
+		}
+		return count
+	}
+	idle := func(sel Selection, after time.Duration) bool {
+		last := sel.LastUsedAt()
+		return after > 0 && !last.IsZero() && at.Sub(last) >= after
+	}
+
+	{
+		filtered := []Selection{}
+		for _, sel := range s.Ephemeral {
+			reason := ""
+			switch {
+			case p.PromoteAfterCopies > 0 && recent(sel.Copies) >= p.PromoteAfterCopies:
+				reason = fmt.Sprintf("copied %d times", recent(sel.Copies))
+			case p.PromoteAfterCaptures > 0 && recent(sel.Captures) >= p.PromoteAfterCaptures:
+				reason = fmt.Sprintf("captured %d times", recent(sel.Captures))
+			}
+			if reason == "" {
+				filtered = append(filtered, sel)
+				continue
+			}
+			s.Important = append(s.Important, sel)
+			transitions = append(transitions, Transition{
+				Entry:  Entry{Selection: sel, Category: SelectionRetentionTypeEphemeral},
+				To:     SelectionRetentionTypeImportant,
+				Reason: reason,
+			})
+		}
+		s.Ephemeral = filtered
+	}
+
+	{
+		filtered := []Selection{}
+		demoted := []Selection{}
+		for _, sel := range s.Important {
+			if !idle(sel, p.DemoteAfter) {
+				filtered = append(filtered, sel)
+				continue
+			}
+			demoted = append(demoted, sel)
+			transitions = append(transitions, Transition{
+				Entry:  Entry{Selection: sel, Category: SelectionRetentionTypeImportant},
+				To:     SelectionRetentionTypeEphemeral,
+				Reason: fmt.Sprintf("not used for %s", at.Sub(sel.LastUsedAt()).Truncate(time.Second)),
+			})
+		}
+		s.Important = filtered
+		// Demoted selections are old, they go before the recent ephemeral ones
+		s.Ephemeral = append(demoted, s.Ephemeral...)
+	}
+
+	{
+		filtered := []Selection{}
+		for _, sel := range s.Ephemeral {
+			if !idle(sel, p.ExpireAfter) || sel.Collected() {
+				filtered = append(filtered, sel)
+				continue
+			}
+			transitions = append(transitions, Transition{
+				Entry:  Entry{Selection: sel, Category: SelectionRetentionTypeEphemeral},
+				Reason: fmt.Sprintf("not used for %s", at.Sub(sel.LastUsedAt()).Truncate(time.Second)),
+			})
+		}
+		s.Ephemeral = filtered
+	}
+
+	s.truncate()
+	return transitions
+}
@@ -0,0 +1,76 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_apply_policy(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	s := NewSelections()
+	add := func(content string) {
+		_, err := s.Add(Selection{
+			Selection:  xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING},
+			CapturedAt: clock,
+		})
+		require.NoError(t, err)
+	}
+	policy := Policy{
+		PromoteAfterCopies:   2,
+		PromoteAfterCaptures: 3,
+		DemoteAfter:          10 * 24 * time.Hour,
+		ExpireAfter:          5 * 24 * time.Hour,
+	}
+
+	assert.Empty(t, s.ApplyPolicy(Policy{}, clock))
+
+	add("copied")
+	add("captured")
+	add("pinned")
+	_, err := s.Pin(s.Entries()[0].ID())
+	require.NoError(t, err)
+	add("other")
+	add("captured")
+	add("other")
+	add("captured")
+	for range 2 {
+		_, ok := s.Copy([]byte("copied"), CopyOptionKeepCategory(true))
+		require.True(t, ok)
+	}
+
+	transitions := s.ApplyPolicy(policy, clock)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "captured", string(transitions[0].Content))
+	assert.Equal(t, SelectionRetentionTypeImportant, transitions[0].To)
+	assert.Equal(t, "captured 3 times", transitions[0].Reason)
+	assert.Equal(t, "copied", string(transitions[1].Content))
+	assert.Equal(t, "copied 2 times", transitions[1].Reason)
+	assert.Equal(t, []string{"copied", "pinned", "other", "captured"}, contents(s.Entries()))
+
+	// Nothing is used for a week, ephemeral selections expire
+	clock = clock.Add(7 * 24 * time.Hour)
+	transitions = s.ApplyPolicy(policy, clock)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, "other", string(transitions[0].Content))
+	assert.Empty(t, transitions[0].To)
+
+	// Important selections are demoted later and are not promoted back by old uses
+	clock = clock.Add(4 * 24 * time.Hour)
+	transitions = s.ApplyPolicy(policy, clock)
+	require.Len(t, transitions, 4)
+	assert.Equal(t, SelectionRetentionTypeEphemeral, transitions[0].To)
+	assert.Equal(t, SelectionRetentionTypeEphemeral, transitions[1].To)
+	assert.Empty(t, transitions[2].To)
+	assert.Empty(t, transitions[3].To)
+	assert.Equal(t, []Selection{}, s.Important)
+	assert.Equal(t, []Selection{}, s.Ephemeral)
+	assert.Len(t, s.Pinned, 1)
+}
@@ -0,0 +1,106 @@
+package selections
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrInvalidRegister is returned for register names other than a single letter from a to z
+var ErrInvalidRegister = errors.New("invalid register")
+
+// Register is a named slot holding a single selection
+type Register struct {
+	Name string
+	Selection
+}
+
+// ValidRegister checks the register name is a single letter from a to z
+func ValidRegister(name string) error {
+	if len(name) != 1 || name[0] < 'a' || name[0] > 'z' {
+		return fmt.Errorf("%w %q, registers are named a to z", ErrInvalidRegister, name)
+	}
+	return nil
+}
+
+// SetRegister stores the selection in the register, replacing its previous content.
+// Registers are kept apart from the categories, they are never evicted nor cleared.
+func (s *Set) SetRegister(name string, selection Selection) error {
+	if err := ValidRegister(name); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.setRegister(name, selection)
+	return nil
+}
+
+// SetRegisterID stores the selection with the given ID in the register, the last selection if the ID is empty
+func (s *Set) SetRegisterID(name string, id string) (Selection, error) {
+	if err := ValidRegister(name); err != nil {
+		return Selection{}, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var selection Selection
+	found := false
+	if id == "" {
+		if s.Last != nil {
+			selection, found = *s.Last, true
+		}
+	} else {
+		selection, found = s.lookup(matchID(id))
+	}
+	if !found {
+		return Selection{}, ErrNoMatch
+	}
+	return s.setRegister(name, selection), nil
+}
+
+// setRegister stores the selection in the register and returns it, the caller must hold the lock
+func (s *Set) setRegister(name string, selection Selection) Selection {
+	if s.Registers == nil {
+		s.Registers = map[string]Selection{}
+	}
+	if selection.CapturedAt.IsZero() {
+		selection.CapturedAt = now()
+	}
+	s.Registers[name] = selection
+	return selection
+}
+
+// GetRegister returns the selection in the register
+func (s *Set) GetRegister(name string) (Selection, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	selection, ok := s.Registers[name]
+	return selection, ok
+}
+
+// ClearRegister empties the register, it reports whether it held a selection
+func (s *Set) ClearRegister(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.Registers[name]
+	delete(s.Registers, name)
+	return ok
+}
+
+// ListRegisters returns the registers holding a selection sorted by name
+func (s *Set) ListRegisters() []Register {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	registers := []Register{}
+	for _, name := range slices.Sorted(maps.Keys(s.Registers)) {
+		registers = append(registers, Register{Name: name, Selection: s.Registers[name]})
+	}
+	return registers
+}
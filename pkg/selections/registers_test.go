@@ -0,0 +1,41 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_registers(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"alpha", "beta"} {
+		_, err := s.Add(Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}})
+		require.NoError(t, err)
+	}
+
+	selection, err := s.SetRegisterID("a", "")
+	require.NoError(t, err)
+	assert.Equal(t, "beta", string(selection.Content))
+	require.NoError(t, s.SetRegister("b", Selection{Selection: xclip.NewSelection([]byte("gamma"), xclip.ValidTargetUTF8_STRING, "")}))
+	_, err = s.SetRegisterID("c", "unknown")
+	assert.ErrorIs(t, err, ErrNoMatch)
+	assert.ErrorIs(t, s.SetRegister("A", Selection{}), ErrInvalidRegister)
+	assert.ErrorIs(t, s.SetRegister("ab", Selection{}), ErrInvalidRegister)
+
+	// Registers are kept apart from the history
+	s.Add(Selection{Selection: xclip.Selection{Content: []byte("delta"), Target: xclip.ValidTargetUTF8_STRING}})
+	s.ClearAll(SelectionRetentionTypeAll)
+	registers := s.ListRegisters()
+	require.Len(t, registers, 2)
+	assert.Equal(t, "a", registers[0].Name)
+	assert.Equal(t, "beta", string(registers[0].Content))
+	assert.Equal(t, "b", registers[1].Name)
+	assert.False(t, registers[1].CapturedAt.IsZero())
+
+	assert.True(t, s.ClearRegister("a"))
+	assert.False(t, s.ClearRegister("a"))
+	_, ok := s.GetRegister("a")
+	assert.False(t, ok)
+}
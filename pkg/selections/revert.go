@@ -0,0 +1,60 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"errors"
+	"log/slog"
+)
+
+// MaxRevertSteps is how many previous owners of each X selection are remembered
+const MaxRevertSteps = 20
+
+// ErrNothingToRevert is returned by Revert when the X selection has no previous owner left in the history
+var ErrNothingToRevert = errors.New("no previous selection to revert to")
+
+// own records the selection as the current owner of its X selection, the caller must hold the lock.
+// Selections without an X selection, such as the ones added by the client, are not recorded.
+func (s *Set) own(selection Selection) {
+	if selection.Clipboard == xclip.ClipboardSelectionUndefined {
+		return
+	}
+	if s.Owners == nil {
+		s.Owners = map[xclip.ClipboardSelection][]string{}
+	}
+	owners := s.Owners[selection.Clipboard]
+	if len(owners) > 0 && owners[len(owners)-1] == selection.ID() {
+		return
+	}
+	owners = append(owners, selection.ID())
+	if len(owners) > MaxRevertSteps+1 {
+		owners = owners[len(owners)-MaxRevertSteps-1:]
+	}
+	s.Owners[selection.Clipboard] = owners
+}
+
+// Revert goes back the given number of steps in the owners of the X selection and returns the selection to own it with.
+// Previous owners no longer in the history are skipped. The selection becomes the last selection
+// so capturing it again does not move it, and the owners after it are forgotten so reverting again goes further back.
+func (s *Set) Revert(clip xclip.ClipboardSelection, steps int) (Selection, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	owners := s.Owners[clip]
+	// The most recent owner is the current content
+	for i := len(owners) - 2; i >= 0; i-- {
+		selection, found := s.lookup(matchID(owners[i]))
+		if !found {
+			owners = append(owners[:i:i], owners[i+1:]...)
+			continue
+		}
+		steps--
+		if steps > 0 {
+			continue
+		}
+		s.Owners[clip] = owners[:i+1]
+		s.Last = &selection
+		slog.Debug("Reverted selection", "selection", clip, "id", selection.ID())
+		return selection, nil
+	}
+	return Selection{}, ErrNothingToRevert
+}
@@ -0,0 +1,78 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_Revert(t *testing.T) {
+	s := NewSelections()
+	capture := func(content string, clip xclip.ClipboardSelection) {
+		s.Add(Selection{Selection: xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, clip)})
+	}
+	capture("one", xclip.ClipboardSelectionClipboard)
+	capture("two", xclip.ClipboardSelectionClipboard)
+	capture("word", xclip.ClipboardSelectionPrimary)
+	capture("three", xclip.ClipboardSelectionClipboard)
+	capture("clobbered", xclip.ClipboardSelectionClipboard)
+	ephemeral := func() []string {
+		result := []string{}
+		for _, sel := range s.Ephemeral {
+			result = append(result, sel.String())
+		}
+		return result
+	}
+	before := ephemeral()
+
+	selection, err := s.Revert(xclip.ClipboardSelectionClipboard, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "three", selection.String())
+	assert.Equal(t, "three", s.Last.String())
+	assert.Equal(t, before, ephemeral(), "categories keep their order")
+
+	// Capturing the reverted content does not move it nor count as a new owner
+	capture("three", xclip.ClipboardSelectionClipboard)
+	assert.Equal(t, before, ephemeral())
+
+	// Owners no longer in the history are skipped
+	s.Clear([]byte("two"), SelectionRetentionTypeAll)
+	selection, err = s.Revert(xclip.ClipboardSelectionClipboard, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "one", selection.String())
+
+	_, err = s.Revert(xclip.ClipboardSelectionClipboard, 1)
+	assert.ErrorIs(t, err, ErrNothingToRevert)
+	_, err = s.Revert(xclip.ClipboardSelectionPrimary, 1)
+	assert.ErrorIs(t, err, ErrNothingToRevert)
+
+	capture("four", xclip.ClipboardSelectionClipboard)
+	capture("five", xclip.ClipboardSelectionClipboard)
+	selection, err = s.Revert(xclip.ClipboardSelectionClipboard, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "one", selection.String())
+}
+
+func TestSet_Revert_while_encoding(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"one", "two"} {
+		s.Add(Selection{Selection: xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard)})
+	}
+
+	// The owners change while the history is saved
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			s.Revert(xclip.ClipboardSelectionClipboard, 1)
+			s.Add(Selection{Selection: xclip.NewSelection([]byte("two"), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard)})
+		}
+	}()
+	for range 100 {
+		require.NoError(t, s.Encode(io.Discard))
+	}
+	<-done
+}
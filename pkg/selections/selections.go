@@ -1,19 +1,35 @@
 package selections
 
 import (
+	"blueclip/pkg/logging"
 	"blueclip/pkg/xclip"
 	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
+	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"image/png"
 	"io"
-	"log"
+	"log/slog"
 	"sync"
+	"time"
 )
 
 type Selection struct {
 	xclip.Selection
+	// CapturedAt is the last time the content was captured, zero for selections stored by older versions
+	CapturedAt time.Time
+	// Label describes the content, such as the command that produced it or a name given by the user
+	Label string
+	// Captures and Copies are the most recent times the content was captured and copied, used to rank by frecency
+	Captures []time.Time
+	Copies   []time.Time
+	// Tags are free-form names to organize the selections
+	Tags []string
+	// Collections are the named collections the selection belongs to, collected selections are never evicted
+	Collections []string
 }
 
 // Line appends a null terminator to the selection
@@ -36,6 +52,12 @@ func (s *Selection) Clean() []byte {
 	return s.Content
 }
 
+// ID identifies the selection by its content, so it is stable across restarts
+func (s *Selection) ID() string {
+	hash := sha256.Sum256(s.Content)
+	return hex.EncodeToString(hash[:6])
+}
+
 func (s *Selection) String() string {
 	return string(s.Content)
 }
@@ -45,14 +67,46 @@ func (s *Selection) Equal(other Selection) bool {
 	return bytes.Equal(s.Content, other.Content)
 }
 
+// Entry is a selection together with the category it is stored in
+type Entry struct {
+	Selection
+	Category SelectionRetentionType
+}
+
 type Set struct {
 	Ephemeral []Selection
 	Important []Selection
-	Last      *Selection
+	// Pinned selections are never evicted and are listed right after the last selection
+	Pinned []Selection
+	Last   *Selection
+	// Trash holds the cleared selections, the oldest batch first
+	Trash []TrashBatch
+	// Owners are the IDs of the selections that owned each X selection, the current owner last
+	Owners map[xclip.ClipboardSelection][]string
+	// Registers hold a selection each by name, from a to z. They are never evicted.
+	Registers map[string]Selection
 
 	Options Options
 
 	lock sync.Mutex
+	// onEvict receives the selections dropped to stay under the limits
+	onEvict func([]Eviction)
+}
+
+// Eviction is a selection dropped to stay under the limits
+type Eviction struct {
+	Entry
+	Reason string
+	// Contained is set when a new selection holds the content, so it is still in the history
+	Contained bool
+}
+
+// OnEvict sets the function that receives the selections dropped to stay under the limits.
+// It is called with the set locked, it must not use the set.
+func (s *Set) OnEvict(fn func([]Eviction)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.onEvict = fn
 }
 
 type SelectionRetentionType string
@@ -61,59 +115,209 @@ const (
 	SelectionRetentionTypeAll       SelectionRetentionType = "all"
 	SelectionRetentionTypeEphemeral SelectionRetentionType = "ephemeral"
 	SelectionRetentionTypeImportant SelectionRetentionType = "important"
+	SelectionRetentionTypePinned    SelectionRetentionType = "pinned"
+	// SelectionRetentionTypeLast is only used for the last selection once it
+	// was cleared from the other categories
+	SelectionRetentionTypeLast SelectionRetentionType = "last"
 )
 
-func (s *Set) ClearAll(typ SelectionRetentionType) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+// ErrAlreadyLast is returned by Add when the selection matches the last selection
+var ErrAlreadyLast = errors.New("selection is already the last selection")
 
-	if typ == SelectionRetentionTypeAll || typ == SelectionRetentionTypeEphemeral {
-		log.Printf("Clearing all ephemeral selections")
-		s.Ephemeral = []Selection{}
+// ErrPinned is returned when moving a pinned selection to another category without unpinning it
+var ErrPinned = errors.New("selection is pinned, unpin it first")
+
+// ErrNotPinned is returned when unpinning a selection that is not pinned
+var ErrNotPinned = errors.New("selection is not pinned")
+
+// categories are the stored categories in list order
+var categories = []SelectionRetentionType{
+	SelectionRetentionTypePinned,
+	SelectionRetentionTypeImportant,
+	SelectionRetentionTypeEphemeral,
+}
+
+// list returns the list that stores the category, nil for categories that are not stored
+func (s *Set) list(typ SelectionRetentionType) *[]Selection {
+	switch typ {
+	case SelectionRetentionTypeEphemeral:
+		return &s.Ephemeral
+	case SelectionRetentionTypeImportant:
+		return &s.Important
+	case SelectionRetentionTypePinned:
+		return &s.Pinned
 	}
-	if typ == SelectionRetentionTypeAll || typ == SelectionRetentionTypeImportant {
-		log.Printf("Clearing all important selections")
-		s.Important = []Selection{}
+	return nil
+}
+
+// ClearAll removes every selection of the given type and returns the removed entries.
+// Pinned selections are only removed if the type is pinned, the all type keeps them.
+// The removed selections are moved to the trash as a single batch unless the clear is permanent.
+func (s *Set) ClearAll(typ SelectionRetentionType, opts ...ClearOption) []Entry {
+	return s.ClearSelected(Selector{}, typ, opts...)
+}
+
+// ClearSelected removes every selection passing the selector filters, the index is not used.
+// The selector category narrows the all type, so pinned selections are cleared by asking for their category.
+func (s *Set) ClearSelected(selector Selector, typ SelectionRetentionType, opts ...ClearOption) []Entry {
+	if typ == SelectionRetentionTypeAll && selector.Category != "" {
+		typ = selector.Category
 	}
+	return s.clearEntries(func(entry Entry) bool {
+		return clears(typ, entry.Category) && selector.Matches(entry)
+	}, opts)
+}
+
+// Clear removes the selections matching the given line and returns the removed entries
+func (s *Set) Clear(line []byte, typ SelectionRetentionType, opts ...ClearOption) []Entry {
+	return s.ClearLines([][]byte{line}, typ, opts...)
 }
 
-func (s *Set) Clear(pattern []byte, typ SelectionRetentionType) bool {
+// ClearLines removes the selections matching any of the lines as a single batch and returns the removed entries
+func (s *Set) ClearLines(lines [][]byte, typ SelectionRetentionType, opts ...ClearOption) []Entry {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	found := false
 
-	if typ == SelectionRetentionTypeAll || typ == SelectionRetentionTypeEphemeral {
-		filtered := []Selection{}
-		for _, sel := range s.Ephemeral {
-			if !bytes.Equal(sel.Clean(), pattern) {
-				filtered = append(filtered, sel)
-			} else {
-				log.Printf("clearing ephemeral selection: %d bytes", len(sel.Content))
-				found = true
+	matchers := make([]func(Selection) bool, len(lines))
+	for i, line := range lines {
+		matchers[i] = s.matchLine(line)
+	}
+	return s.removeEntries(matchType(typ, func(sel Selection) bool {
+		for _, match := range matchers {
+			if match(sel) {
+				return true
 			}
 		}
-		s.Ephemeral = filtered
+		return false
+	}), opts)
+}
+
+// ClearID removes the selection with the given ID and returns the removed entries
+func (s *Set) ClearID(id string, typ SelectionRetentionType, opts ...ClearOption) []Entry {
+	return s.clearMatch(matchID(id), typ, opts)
+}
+
+func (s *Set) clearMatch(match func(Selection) bool, typ SelectionRetentionType, opts []ClearOption) []Entry {
+	return s.clearEntries(matchType(typ, match), opts)
+}
+
+// matchType matches the entries of the type that match the selection
+func matchType(typ SelectionRetentionType, match func(Selection) bool) func(Entry) bool {
+	return func(entry Entry) bool {
+		return clears(typ, entry.Category) && match(entry.Selection)
 	}
+}
+
+// clears reports whether clearing the type removes selections of the category.
+// Every clear follows it, the all type never removes pinned selections.
+func clears(typ SelectionRetentionType, category SelectionRetentionType) bool {
+	return typ == category || (typ == SelectionRetentionTypeAll && category != SelectionRetentionTypePinned)
+}
 
-	if typ == SelectionRetentionTypeAll || typ == SelectionRetentionTypeImportant {
+// clearEntries removes the entries passing the filter, unless it is a dry run, and returns them
+func (s *Set) clearEntries(match func(Entry) bool, opts []ClearOption) []Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.removeEntries(match, opts)
+}
+
+// removeEntries is clearEntries for callers holding the lock
+func (s *Set) removeEntries(match func(Entry) bool, opts []ClearOption) []Entry {
+	options := newClearOptions(opts)
+	removed := []TrashedEntry{}
+
+	for _, category := range categories {
+		list := s.list(category)
 		filtered := []Selection{}
-		for _, sel := range s.Important {
-			if !bytes.Equal(sel.Clean(), pattern) {
+		for i, sel := range *list {
+			entry := Entry{Selection: sel, Category: category}
+			if !match(entry) {
 				filtered = append(filtered, sel)
-			} else {
-				log.Printf("clearing important selection: %d bytes", len(sel.Content))
-				found = true
+				continue
 			}
+			removed = append(removed, TrashedEntry{Entry: entry, Position: i})
+			if !options.dryRun {
+				slog.Info("Clearing selection", "category", category, logging.Content("selection", sel.Content))
+			}
+		}
+		if !options.dryRun {
+			*list = filtered
 		}
-		s.Important = filtered
 	}
 
-	return found
+	if !options.dryRun {
+		s.trash(removed, options)
+	}
+	return entriesOf(removed)
+}
+
+func entriesOf(trashed []TrashedEntry) []Entry {
+	entries := make([]Entry, len(trashed))
+	for i, t := range trashed {
+		entries[i] = t.Entry
+	}
+	return entries
+}
+
+// Usage is the number of entries and the bytes they use
+type Usage struct {
+	Count int `json:"count"`
+	Bytes int `json:"bytes"`
+}
+
+func (u *Usage) add(sel Selection) {
+	u.Count++
+	u.Bytes += len(sel.Content)
+}
+
+// Stats summarizes the size of the stored selections
+type Stats struct {
+	Categories map[SelectionRetentionType]Usage `json:"categories"`
+	Targets    map[xclip.ValidTarget]Usage      `json:"targets"`
+	Total      Usage                            `json:"total"`
+	// Limits are the limits the usage is kept under
+	Limits Options `json:"limits"`
+}
+
+func (s *Set) Stats() Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := Stats{
+		Categories: map[SelectionRetentionType]Usage{},
+		Targets:    map[xclip.ValidTarget]Usage{},
+		Limits:     s.Options,
+	}
+	for _, typ := range categories {
+		category := stats.Categories[typ]
+		for _, sel := range *s.list(typ) {
+			category.add(sel)
+			stats.Total.add(sel)
+			target := stats.Targets[sel.Target]
+			target.add(sel)
+			stats.Targets[sel.Target] = target
+		}
+		stats.Categories[typ] = category
+	}
+	return stats
 }
 
 type Options struct {
-	MaxEphemeralElements int
-	MaxImportantElements int
+	MaxEphemeralElements int `json:"max_ephemeral"`
+	MaxImportantElements int `json:"max_important"`
+	// Budget limits the bytes used on top of the element counts
+	Budget Budget `json:"budget"`
+	// TrashRetention is how long cleared selections are kept in the trash, zero disables the trash
+	TrashRetention time.Duration `json:"trash_retention"`
+}
+
+// DefaultOptions are the limits of a new set
+func DefaultOptions() Options {
+	return Options{
+		MaxEphemeralElements: 200,
+		MaxImportantElements: 100,
+		TrashRetention:       7 * 24 * time.Hour,
+	}
 }
 
 func NewSelections() *Set {
@@ -121,28 +325,60 @@ func NewSelections() *Set {
 		// Selections at the end of the list are the most recent
 		Ephemeral: []Selection{},
 		Important: []Selection{},
-		Options: Options{
-			MaxEphemeralElements: 200,
-			MaxImportantElements: 100,
-		},
+		Pinned:    []Selection{},
+		Trash:     []TrashBatch{},
+		Owners:    map[xclip.ClipboardSelection][]string{},
+		Registers: map[string]Selection{},
+		Options:   DefaultOptions(),
 	}
 }
 
-func (s *Set) Add(selection Selection) {
+// Encode writes the set with gob while holding the lock, so it can be saved while it changes
+func (s *Set) Encode(w io.Writer) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	return gob.NewEncoder(w).Encode(s)
+}
 
-	log.Printf("Adding selection: %d bytes", len(selection.Content))
+// SetOptions changes the limits, selections over the new limits are dropped right away
+func (s *Set) SetOptions(options Options) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Options = options
+	s.truncate()
+}
+
+// Add stores a new selection and returns the category it ended up in
+func (s *Set) Add(selection Selection) (SelectionRetentionType, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	slog.Debug("Adding selection", logging.Content("selection", selection.Content), "target", selection.Target)
+	s.own(selection)
 
 	if s.Last != nil {
 		if selection.Equal(*s.Last) {
-			log.Printf("Selected content is already in the last selection")
-			return
+			slog.Debug("Selected content is already in the last selection")
+			return "", ErrAlreadyLast
 		}
 	}
-	log.Printf("Setting last selection")
+	if previous, ok := s.lookup(selection.Equal); ok {
+		selection.inherit(previous)
+	}
+	selection.Captures = recordUse(selection.Captures, selection.CapturedAt)
+
+	slog.Debug("Setting last selection")
 	s.Last = &selection
 
+	for i, sel := range s.Pinned {
+		if selection.Equal(sel) {
+			slog.Debug("Selected content is pinned")
+			s.Pinned[i] = selection
+			return SelectionRetentionTypePinned, nil
+		}
+	}
+
 	isImportant := false
 	{
 		filtered := []Selection{}
@@ -151,7 +387,7 @@ func (s *Set) Add(selection Selection) {
 				filtered = append(filtered, sel)
 			} else {
 				isImportant = true
-				log.Printf("Selected content is already in the important list")
+				slog.Debug("Selected content is already in the important list")
 			}
 		}
 		if isImportant {
@@ -161,14 +397,20 @@ func (s *Set) Add(selection Selection) {
 		}
 	}
 
+	// Selections contained in the new one are dropped, except collected ones
+	contained := []Selection{}
 	{
 		filtered := []Selection{}
 
 		for _, sel := range s.Ephemeral {
-			if !bytes.Contains(selection.Content, sel.Content) {
+			switch {
+			case selection.Equal(sel):
+				// Replaced by the new selection
+			case sel.Collected() || !bytes.Contains(selection.Content, sel.Content):
 				filtered = append(filtered, sel)
-			} else {
-				log.Printf("Dropping existing selection as it's contained in new selection")
+			default:
+				slog.Debug("Dropping existing selection as it's contained in new selection", logging.Content("dropped", sel.Content))
+				contained = append(contained, sel)
 			}
 		}
 
@@ -179,77 +421,374 @@ func (s *Set) Add(selection Selection) {
 		}
 	}
 
-	if len(s.Ephemeral) > s.Options.MaxEphemeralElements {
-		log.Printf("Truncating ephemeral list to %d elements", s.Options.MaxEphemeralElements)
-		s.Ephemeral = s.Ephemeral[len(s.Ephemeral)-s.Options.MaxEphemeralElements:]
+	dropped := appendEvictions(nil, contained, SelectionRetentionTypeEphemeral, "contained in a new selection")
+	for i := range dropped {
+		dropped[i].Contained = true
 	}
-	if len(s.Important) > s.Options.MaxImportantElements {
-		log.Printf("Truncating important list to %d elements", s.Options.MaxImportantElements)
-		s.Important = s.Important[len(s.Important)-s.Options.MaxImportantElements:]
+	s.truncate(dropped...)
+
+	slog.Debug("Selection added", "ephemeral", len(s.Ephemeral), "important", len(s.Important))
+
+	if isImportant {
+		return SelectionRetentionTypeImportant, nil
 	}
+	return SelectionRetentionTypeEphemeral, nil
+}
 
-	log.Printf("Ephemeral length: %d", len(s.Ephemeral))
-	log.Printf("Important length: %d", len(s.Important))
+// Insert stores a selection in the given category without changing the last selection.
+// A selection with the same content is replaced.
+func (s *Set) Insert(selection Selection, typ SelectionRetentionType) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	list := s.list(typ)
+	if list == nil {
+		return fmt.Errorf("invalid category %s, allowed categories are: ephemeral, important, pinned", typ)
+	}
+	if previous, ok := s.lookup(selection.Equal); ok {
+		selection.inherit(previous)
+	}
+	selection.Captures = recordUse(selection.Captures, selection.CapturedAt)
+	for _, category := range categories {
+		s.remove(category, selection.Equal)
+	}
+	*list = append(*list, selection)
+	s.truncate()
+
+	slog.Debug("Selection inserted", "category", typ, "ephemeral", len(s.Ephemeral), "important", len(s.Important))
+	return nil
 }
 
-func (s *Set) List(out io.Writer) {
-	log.Printf("Listing %d important and %d ephemeral selections", len(s.Important), len(s.Ephemeral))
+// Demote moves the selection with the given ID to the ephemeral list
+func (s *Set) Demote(id string) (Selection, error) {
+	return s.move(id, SelectionRetentionTypeEphemeral, func(from SelectionRetentionType) error {
+		if from == SelectionRetentionTypePinned {
+			return ErrPinned
+		}
+		return nil
+	})
+}
 
-	// Get max length to know how many iterations we need
-	maxLen := len(s.Important)
-	if len(s.Ephemeral) > maxLen {
-		maxLen = len(s.Ephemeral)
+// Pin moves the selection with the given ID to the pinned list, where it is never evicted
+func (s *Set) Pin(id string) (Selection, error) {
+	return s.move(id, SelectionRetentionTypePinned, nil)
+}
+
+// Unpin moves the pinned selection with the given ID back to the important list
+func (s *Set) Unpin(id string) (Selection, error) {
+	return s.move(id, SelectionRetentionTypeImportant, func(from SelectionRetentionType) error {
+		if from != SelectionRetentionTypePinned {
+			return ErrNotPinned
+		}
+		return nil
+	})
+}
+
+// move moves the selection with the given ID to the most recent position of a category.
+// check can reject the move depending on the current category. The last selection can be
+// moved even if it was cleared from the other categories.
+func (s *Set) move(id string, to SelectionRetentionType, check func(from SelectionRetentionType) error) (Selection, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var sel *Selection
+	from := SelectionRetentionTypeLast
+	for _, category := range categories {
+		for _, other := range *s.list(category) {
+			if other.ID() == id {
+				sel = &other
+				from = category
+				break
+			}
+		}
+		if sel != nil {
+			break
+		}
+	}
+	if sel == nil && s.Last != nil && s.Last.ID() == id {
+		sel = s.Last
+	}
+	if sel == nil {
+		return Selection{}, ErrNoMatch
+	}
+	if check != nil {
+		if err := check(from); err != nil {
+			return Selection{}, err
+		}
+	}
+
+	if from != SelectionRetentionTypeLast {
+		s.remove(from, matchID(id))
+	}
+	list := s.list(to)
+	*list = append(*list, *sel)
+	s.truncate()
+	slog.Debug("Selection moved", "from", from, "to", to)
+	return *sel, nil
+}
+
+// remove drops the matching selections from the category, the caller must hold the lock
+func (s *Set) remove(typ SelectionRetentionType, match func(Selection) bool) {
+	list := s.list(typ)
+	filtered := []Selection{}
+	for _, sel := range *list {
+		if !match(sel) {
+			filtered = append(filtered, sel)
+		}
 	}
+	*list = filtered
+}
+
+// SetLast marks the selection as the last selection, as if it had been captured
+func (s *Set) SetLast(selection Selection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Last = &selection
+}
+
+// truncate drops the oldest selections over the limits, the caller must hold the lock.
+// evicted are the selections already dropped by the caller, reported along with the others.
+func (s *Set) truncate(evicted ...Eviction) {
+	evicted = append(evicted, truncateList(&s.Ephemeral, s.Options.MaxEphemeralElements, SelectionRetentionTypeEphemeral)...)
+	evicted = append(evicted, truncateList(&s.Important, s.Options.MaxImportantElements, SelectionRetentionTypeImportant)...)
+	evicted = append(evicted, s.enforceBudget()...)
+
+	if len(evicted) > 0 && s.onEvict != nil {
+		s.onEvict(evicted)
+	}
+}
+
+// truncateList drops the oldest selections over the limit. Collected selections are never
+// dropped and don't count towards the limit.
+func truncateList(list *[]Selection, max int, typ SelectionRetentionType) []Eviction {
+	over := -max
+	for _, sel := range *list {
+		if !sel.Collected() {
+			over++
+		}
+	}
+	if over <= 0 {
+		return nil
+	}
+
+	slog.Info("Truncating "+string(typ)+" list", "max", max)
+	kept := []Selection{}
+	dropped := []Selection{}
+	for _, sel := range *list {
+		if over > 0 && !sel.Collected() {
+			dropped = append(dropped, sel)
+			over--
+			continue
+		}
+		kept = append(kept, sel)
+	}
+	*list = kept
+	return appendEvictions(nil, dropped, typ, string(typ)+" limit")
+}
+
+func appendEvictions(evicted []Eviction, dropped []Selection, typ SelectionRetentionType, reason string) []Eviction {
+	for _, sel := range dropped {
+		evicted = append(evicted, Eviction{Entry: Entry{Selection: sel, Category: typ}, Reason: reason})
+	}
+	return evicted
+}
+
+// Entries returns the selections in list order. The last selection comes first,
+// followed by pinned selections and important and ephemeral selections interleaved from the most recent.
+func (s *Set) Entries() []Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.entries()
+}
 
-	// Last selection is always written first
+// entries returns the selections in list order, the caller must hold the lock
+func (s *Set) entries() []Entry {
+	entries := []Entry{}
+
+	// Last selection is always first
 	if s.Last != nil {
-		out.Write(s.Last.Line())
+		entries = append(entries, Entry{Selection: *s.Last, Category: s.categoryOf(*s.Last)})
+	}
+	isLast := func(sel Selection) bool {
+		return s.Last != nil && sel.Equal(*s.Last)
+	}
+
+	// Followed by the pinned selections
+	for i := len(s.Pinned) - 1; i >= 0; i-- {
+		if !isLast(s.Pinned[i]) {
+			entries = append(entries, Entry{Selection: s.Pinned[i], Category: SelectionRetentionTypePinned})
+		}
 	}
 
+	// Get max length to know how many iterations we need
+	maxLen := max(len(s.Important), len(s.Ephemeral))
+
 	// Iterate from the end of both slices
 	for i := 0; i < maxLen; i++ {
-		// Write Important second if available
+		// Important goes first if available
 		importantIdx := len(s.Important) - 1 - i
-		if importantIdx >= 0 {
-			if s.Last != nil && !s.Important[importantIdx].Equal(*s.Last) {
-				_, err := out.Write(s.Important[importantIdx].Line())
-				if err != nil {
-					log.Printf("Failed to write important selection: %v", err)
-				}
-			}
+		if importantIdx >= 0 && !isLast(s.Important[importantIdx]) {
+			entries = append(entries, Entry{Selection: s.Important[importantIdx], Category: SelectionRetentionTypeImportant})
 		}
 
-		// Write Ephemeral first if available
+		// Ephemeral goes second if available
 		ephemeralIdx := len(s.Ephemeral) - 1 - i
-		if ephemeralIdx >= 0 {
-			if s.Last != nil && !s.Ephemeral[ephemeralIdx].Equal(*s.Last) {
-				_, err := out.Write(s.Ephemeral[ephemeralIdx].Line())
-				if err != nil {
-					log.Printf("Failed to write ephemeral selection: %v", err)
-				}
-			}
+		if ephemeralIdx >= 0 && !isLast(s.Ephemeral[ephemeralIdx]) {
+			entries = append(entries, Entry{Selection: s.Ephemeral[ephemeralIdx], Category: SelectionRetentionTypeEphemeral})
 		}
 	}
+	return entries
 }
 
-func (s *Set) Copy(line []byte) (Selection, bool) {
+// Category returns where the selection is stored, last if it is only the last selection
+func (s *Set) Category(sel Selection) SelectionRetentionType {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	return s.categoryOf(sel)
+}
 
+// categoryOf returns where the selection is stored, the caller must hold the lock
+func (s *Set) categoryOf(sel Selection) SelectionRetentionType {
+	for _, other := range s.Pinned {
+		if sel.Equal(other) {
+			return SelectionRetentionTypePinned
+		}
+	}
+	for _, other := range s.Important {
+		if sel.Equal(other) {
+			return SelectionRetentionTypeImportant
+		}
+	}
+	for _, other := range s.Ephemeral {
+		if sel.Equal(other) {
+			return SelectionRetentionTypeEphemeral
+		}
+	}
+	return SelectionRetentionTypeLast
+}
+
+func (s *Set) List(out io.Writer) {
+	WriteLines(out, s.Entries())
+}
+
+// WriteLines writes the null terminated line of every entry
+func WriteLines(out io.Writer, entries []Entry) {
+	slog.Debug("Listing selections", "entries", len(entries))
+
+	for _, entry := range entries {
+		_, err := out.Write(entry.Line())
+		if err != nil {
+			slog.Warn("Failed to write selection", "category", entry.Category, "error", err)
+		}
+	}
+}
+
+// matchLine matches selections by the line written by List. Content ending in a new line is
+// matched as is first, then the terminator added by List is ignored. The caller must hold the lock.
+func (s *Set) matchLine(line []byte) func(Selection) bool {
+	exact := func(sel Selection) bool {
+		return bytes.Equal(line, sel.Clean())
+	}
+	if _, found := s.lookup(exact); found {
+		return exact
+	}
+	cleanLine := trimTerminator(line)
+	return func(sel Selection) bool {
+		return bytes.Equal(cleanLine, sel.Clean())
+	}
+}
+
+// matchID matches selections by their ID
+func matchID(id string) func(Selection) bool {
+	return func(sel Selection) bool {
+		return sel.ID() == id
+	}
+}
+
+type copyOptions struct {
+	keepCategory bool
+}
+
+type CopyOption func(*copyOptions)
+
+// CopyOptionKeepCategory keeps the copied selection in its category, it only becomes
+// the most recent selection of it. By default ephemeral selections are moved to the important list.
+func CopyOptionKeepCategory(keep bool) CopyOption {
+	return func(o *copyOptions) {
+		o.keepCategory = keep
+	}
+}
+
+// Copy marks the selection matching the line as the last selection and moves it to the important list
+func (s *Set) Copy(line []byte, opts ...CopyOption) (Selection, bool) {
 	// Handle empty line
 	if len(line) == 0 {
 		return Selection{}, false
 	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.copyMatch(s.matchLine(line), opts...)
+}
 
-	// Remove null terminator if present
-	cleanLine := line
-	if line[len(line)-1] == '\000' || line[len(line)-1] == '\n' {
-		cleanLine = line[:len(line)-1]
+// CopyID is like Copy but finds the selection by its ID
+func (s *Set) CopyID(id string, opts ...CopyOption) (Selection, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.copyMatch(matchID(id), opts...)
+}
+
+// copyMatch copies the first matching selection, the caller must hold the lock
+func (s *Set) copyMatch(match func(Selection) bool, opts ...CopyOption) (Selection, bool) {
+	options := copyOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for _, category := range categories {
+		// Pinned selections stay pinned
+		if !options.keepCategory && category != SelectionRetentionTypePinned {
+			continue
+		}
+		list := s.list(category)
+		for i, sel := range *list {
+			if match(sel) {
+				sel.Copies = recordUse(sel.Copies, now())
+				*list = append(append((*list)[:i:i], (*list)[i+1:]...), sel)
+				s.Last = &sel
+				return sel, true
+			}
+		}
+	}
+	if options.keepCategory {
+		return Selection{}, false
 	}
 
+	sel, found := s.promoteMatch(match)
+	if found {
+		// promoteMatch moves the selection to the end of the important list
+		sel.Copies = recordUse(sel.Copies, now())
+		s.Important[len(s.Important)-1] = sel
+		s.Last = &sel
+	}
+	return sel, found
+}
+
+// Promote moves the selection with the given ID to the important list
+func (s *Set) Promote(id string) (Selection, error) {
+	return s.move(id, SelectionRetentionTypeImportant, func(from SelectionRetentionType) error {
+		if from == SelectionRetentionTypePinned {
+			return ErrPinned
+		}
+		return nil
+	})
+}
+
+// promoteMatch moves the matching selection to the most recent position of
+// the important list, the caller must hold the lock
+func (s *Set) promoteMatch(match func(Selection) bool) (Selection, bool) {
 	for i, selection := range s.Important {
-		if bytes.Equal(cleanLine, selection.Clean()) {
-			s.Last = &selection
+		if match(selection) {
 			s.Important = append(s.Important[:i], s.Important[i+1:]...)
 			s.Important = append(s.Important, selection)
 			return selection, true
@@ -260,8 +799,8 @@ func (s *Set) Copy(line []byte) (Selection, bool) {
 	found := false
 	filtered := []Selection{}
 	for _, selection := range s.Ephemeral {
-		if bytes.Equal(cleanLine, selection.Clean()) {
-			log.Printf("Moving selection to important list")
+		if match(selection) {
+			slog.Debug("Moving selection to important list")
 			sel = selection
 			found = true
 			s.Important = append(s.Important, selection)
@@ -271,39 +810,59 @@ func (s *Set) Copy(line []byte) (Selection, bool) {
 	}
 	s.Ephemeral = filtered
 
-	if found {
-		s.Last = &sel
-		return sel, true
-	}
-
-	return Selection{}, false
+	return sel, found
 }
 
 func (s *Set) FindMatch(line []byte) (Selection, bool) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-
 	// Handle empty line
 	if len(line) == 0 {
 		return Selection{}, false
 	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lookup(s.matchLine(line))
+}
+
+// FindID returns the selection with the given ID
+func (s *Set) FindID(id string) (Selection, bool) {
+	return s.find(matchID(id))
+}
+
+func (s *Set) find(match func(Selection) bool) (Selection, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lookup(match)
+}
 
-	// Remove null terminator if present
-	cleanLine := line
-	if line[len(line)-1] == '\000' || line[len(line)-1] == '\n' {
-		cleanLine = line[:len(line)-1]
+// lookup returns the first matching selection, the caller must hold the lock
+func (s *Set) lookup(match func(Selection) bool) (Selection, bool) {
+	for _, selection := range s.Pinned {
+		if match(selection) {
+			return selection, true
+		}
 	}
 
 	for _, selection := range s.Important {
-		if bytes.Equal(cleanLine, selection.Clean()) {
+		if match(selection) {
 			return selection, true
 		}
 	}
 
 	for _, selection := range s.Ephemeral {
-		if bytes.Equal(cleanLine, selection.Clean()) {
+		if match(selection) {
 			return selection, true
 		}
 	}
+	if s.Last != nil && match(*s.Last) {
+		return *s.Last, true
+	}
 	return Selection{}, false
 }
+
+// trimTerminator removes the null terminator or new line added by Line, if present
+func trimTerminator(line []byte) []byte {
+	if len(line) > 0 && (line[len(line)-1] == '\000' || line[len(line)-1] == '\n') {
+		return line[:len(line)-1]
+	}
+	return line
+}
@@ -318,7 +318,7 @@ func TestSet_clear_ephemeral(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Selection B", string(sel.Content))
 
-	s.Clear("Selection A\000", SelectionRetentionTypeEphemeral)
+	s.Clear([]byte("Selection A\000"), SelectionRetentionTypeEphemeral)
 
 	buf := bytes.NewBuffer(nil)
 	s.List(buf)
@@ -331,6 +331,24 @@ func TestSet_clear_ephemeral(t *testing.T) {
 	require.Equal(t, "Selection B", string(s.Last.Content))
 }
 
+func TestSet_clear_content_ending_in_new_line(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"ls -la\n", "ls -la", "pwd\n"} {
+		s.Add(Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}})
+	}
+
+	// The exact content wins over the line without its terminator
+	removed := s.Clear([]byte("ls -la\n"), SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "ls -la\n", string(removed[0].Content))
+
+	removed = s.Clear([]byte("pwd\n"), SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	removed = s.Clear([]byte("ls -la\n"), SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "ls -la", string(removed[0].Content))
+}
+
 func TestSet_clear_important(t *testing.T) {
 	s := NewSelections()
 	s.Add(Selection{
@@ -350,7 +368,7 @@ func TestSet_clear_important(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Selection B", string(sel.Content))
 
-	s.Clear("Selection B\000", SelectionRetentionTypeImportant)
+	s.Clear([]byte("Selection B\000"), SelectionRetentionTypeImportant)
 
 	require.Len(t, s.Important, 0)
 	require.Len(t, s.Ephemeral, 1)
@@ -378,7 +396,7 @@ func TestSet_clear_all(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Selection B", string(sel.Content))
 
-	s.Clear("Selection B\000", SelectionRetentionTypeAll)
+	s.Clear([]byte("Selection B\000"), SelectionRetentionTypeAll)
 
 	require.Len(t, s.Important, 0)
 	require.Len(t, s.Ephemeral, 1)
@@ -386,7 +404,7 @@ func TestSet_clear_all(t *testing.T) {
 	// Last selection is preserved
 	require.Equal(t, "Selection B", string(s.Last.Content))
 
-	s.Clear("Selection A\000", SelectionRetentionTypeAll)
+	s.Clear([]byte("Selection A\000"), SelectionRetentionTypeAll)
 
 	require.Len(t, s.Important, 0)
 	require.Len(t, s.Ephemeral, 0)
@@ -407,3 +425,235 @@ func TestSet_clear_all(t *testing.T) {
 
 	require.Equal(t, "Selection C", string(s.Last.Content))
 }
+
+func TestSet_stats(t *testing.T) {
+	s := NewSelections()
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection A"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("image"),
+			Target:  xclip.ValidTargetImagePng,
+		},
+	})
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection B"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+
+	_, ok := s.Copy([]byte("Selection B\000"))
+	require.True(t, ok)
+
+	stats := s.Stats()
+	assert.Equal(t, Usage{Count: 2, Bytes: 16}, stats.Categories[SelectionRetentionTypeEphemeral])
+	assert.Equal(t, Usage{Count: 1, Bytes: 11}, stats.Categories[SelectionRetentionTypeImportant])
+	assert.Equal(t, Usage{Count: 2, Bytes: 22}, stats.Targets[xclip.ValidTargetUTF8_STRING])
+	assert.Equal(t, Usage{Count: 1, Bytes: 5}, stats.Targets[xclip.ValidTargetImagePng])
+}
+
+func TestSet_entries_by_id(t *testing.T) {
+	s := NewSelections()
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection A"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection B"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection C"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+
+	entries := s.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "Selection C", string(entries[0].Content))
+	assert.Equal(t, SelectionRetentionTypeEphemeral, entries[0].Category)
+	idA := entries[2].ID()
+	assert.Len(t, idA, 12)
+
+	sel, err := s.Promote(idA)
+	require.NoError(t, err)
+	assert.Equal(t, "Selection A", string(sel.Content))
+	// Promoting does not change the last selection
+	assert.Equal(t, "Selection C", string(s.Last.Content))
+
+	entries = s.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "Selection A", string(entries[1].Content))
+	assert.Equal(t, SelectionRetentionTypeImportant, entries[1].Category)
+
+	found, ok := s.FindID(idA)
+	require.True(t, ok)
+	assert.Equal(t, "Selection A", string(found.Content))
+
+	removed := s.ClearID(entries[2].ID(), SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "Selection B", string(removed[0].Content))
+
+	sel, ok = s.CopyID(idA)
+	require.True(t, ok)
+	assert.Equal(t, "Selection A", string(s.Last.Content))
+
+	_, ok = s.CopyID("unknown")
+	require.False(t, ok)
+}
+
+func TestSet_insert(t *testing.T) {
+	s := NewSelections()
+	s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection A"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+
+	err := s.Insert(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection B"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	}, SelectionRetentionTypeImportant)
+	require.NoError(t, err)
+	// Inserting does not change the last selection
+	assert.Equal(t, "Selection A", string(s.Last.Content))
+	require.Len(t, s.Important, 1)
+	assert.Equal(t, "Selection B", string(s.Important[0].Content))
+
+	// Inserting existing content moves it to the new category
+	err = s.Insert(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection B"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	}, SelectionRetentionTypeEphemeral)
+	require.NoError(t, err)
+	assert.Empty(t, s.Important)
+	require.Len(t, s.Ephemeral, 2)
+	assert.Equal(t, "Selection B", string(s.Ephemeral[1].Content))
+
+	err = s.Insert(Selection{}, SelectionRetentionTypeAll)
+	assert.Error(t, err)
+}
+
+func TestSet_pinned(t *testing.T) {
+	s := NewSelections()
+	s.Options.MaxImportantElements = 1
+	for _, content := range []string{"Selection A", "Selection B", "Selection C"} {
+		s.Add(Selection{
+			Selection: xclip.Selection{
+				Content: []byte(content),
+				Target:  xclip.ValidTargetUTF8_STRING,
+			},
+		})
+	}
+	idA := s.Entries()[2].ID()
+	idB := s.Entries()[1].ID()
+
+	_, err := s.Pin(idA)
+	require.NoError(t, err)
+
+	// Pinned selections are listed right after the last selection
+	entries := s.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "Selection C", string(entries[0].Content))
+	assert.Equal(t, "Selection A", string(entries[1].Content))
+	assert.Equal(t, SelectionRetentionTypePinned, entries[1].Category)
+
+	_, err = s.Promote(idA)
+	assert.ErrorIs(t, err, ErrPinned)
+	_, err = s.Demote(idA)
+	assert.ErrorIs(t, err, ErrPinned)
+	_, err = s.Unpin(idB)
+	assert.ErrorIs(t, err, ErrNotPinned)
+
+	// Pinned selections are not evicted by the important limit
+	_, err = s.Promote(idB)
+	require.NoError(t, err)
+	_, err = s.Promote(s.Entries()[0].ID())
+	require.NoError(t, err)
+	assert.Len(t, s.Important, 1)
+	assert.Len(t, s.Pinned, 1)
+
+	// Capturing pinned content keeps it pinned
+	category, err := s.Add(Selection{
+		Selection: xclip.Selection{
+			Content: []byte("Selection A"),
+			Target:  xclip.ValidTargetUTF8_STRING,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SelectionRetentionTypePinned, category)
+	assert.Len(t, s.Pinned, 1)
+
+	// Clearing everything keeps the pinned selections
+	s.ClearAll(SelectionRetentionTypeAll)
+	assert.Len(t, s.Pinned, 1)
+
+	_, err = s.Unpin(idA)
+	require.NoError(t, err)
+	assert.Empty(t, s.Pinned)
+	_, err = s.Demote(idA)
+	require.NoError(t, err)
+	assert.Equal(t, "Selection A", string(s.Ephemeral[len(s.Ephemeral)-1].Content))
+}
+
+func TestSet_clear_keeps_pinned(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"pinned", "other"} {
+		s.Add(Selection{Selection: xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, "")})
+	}
+	id := s.Entries()[1].ID()
+	_, err := s.Pin(id)
+	require.NoError(t, err)
+
+	// Every clear of the all type keeps the pinned selections
+	assert.Empty(t, s.ClearID(id, SelectionRetentionTypeAll))
+	assert.Empty(t, s.Clear([]byte("pinned"), SelectionRetentionTypeAll))
+	assert.Len(t, s.ClearAll(SelectionRetentionTypeAll), 1)
+	require.Len(t, s.Pinned, 1)
+
+	// Unless the pinned category is asked for
+	removed := s.ClearSelected(Selector{Category: SelectionRetentionTypePinned}, SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "pinned", string(removed[0].Content))
+	assert.Empty(t, s.Pinned)
+}
+
+func TestSet_copy_keep_category(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"Selection A", "Selection B", "Selection C"} {
+		s.Add(Selection{
+			Selection: xclip.Selection{
+				Content: []byte(content),
+				Target:  xclip.ValidTargetUTF8_STRING,
+			},
+		})
+	}
+
+	sel, ok := s.Copy([]byte("Selection A\000"), CopyOptionKeepCategory(true))
+	require.True(t, ok)
+	assert.Equal(t, "Selection A", string(sel.Content))
+	assert.Equal(t, "Selection A", string(s.Last.Content))
+	assert.Empty(t, s.Important)
+	// It becomes the most recent ephemeral selection
+	require.Len(t, s.Ephemeral, 3)
+	assert.Equal(t, "Selection A", string(s.Ephemeral[2].Content))
+	assert.Equal(t, "Selection B", string(s.Ephemeral[0].Content))
+
+	_, ok = s.Copy([]byte("unknown\000"), CopyOptionKeepCategory(true))
+	assert.False(t, ok)
+}
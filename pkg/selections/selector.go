@@ -0,0 +1,92 @@
+package selections
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/xclip"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrNoMatch    = errors.New("no selection matches")
+	ErrAmbiguous  = errors.New("selector is ambiguous")
+	ErrOutOfRange = errors.New("index out of range")
+)
+
+// Selector picks a single entry in list order, the same order list uses
+type Selector struct {
+	// Index is the position among the matching entries, 0 is the most recent.
+	// If nil the filters must match a single entry.
+	Index *int
+	// Category filters by category, empty or all for any
+	Category SelectionRetentionType
+	// Target filters by target, empty for any
+	Target xclip.ValidTarget
+	// Match filters by content, nil for any
+	Match *regexp.Regexp
+	// OlderThan filters by the time since the last capture, zero for any.
+	// Selections stored by older versions have no capture time and are always older.
+	OlderThan time.Duration
+	// LargerThan filters by content size, zero for any
+	LargerThan bytesize.Size
+	// Filter is an extra condition such as a parsed filter query, nil for any
+	Filter func(Entry) bool
+	// Order ranks the entries before applying the index, empty for the default order
+	Order Order
+}
+
+// Matches reports whether the entry passes the filters, the index is not used
+func (s Selector) Matches(entry Entry) bool {
+	if s.Category != "" && s.Category != SelectionRetentionTypeAll && s.Category != entry.Category {
+		return false
+	}
+	if s.Target != "" && s.Target != entry.Target {
+		return false
+	}
+	if s.Match != nil && !s.Match.Match(entry.Clean()) {
+		return false
+	}
+	if s.OlderThan > 0 && now().Sub(entry.CapturedAt) <= s.OlderThan {
+		return false
+	}
+	if s.LargerThan > 0 && bytesize.Size(len(entry.Content)) <= s.LargerThan {
+		return false
+	}
+	if s.Filter != nil && !s.Filter(entry) {
+		return false
+	}
+	return true
+}
+
+// Select returns the entry picked by the selector
+func (s *Set) Select(selector Selector) (Entry, error) {
+	entries, err := s.Ranked(selector.Order, time.Now())
+	if err != nil {
+		return Entry{}, err
+	}
+	matching := []Entry{}
+	for _, entry := range entries {
+		if selector.Matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+
+	if selector.Index != nil {
+		index := *selector.Index
+		if index < 0 || index >= len(matching) {
+			return Entry{}, fmt.Errorf("%w: index %d, %d selections match", ErrOutOfRange, index, len(matching))
+		}
+		return matching[index], nil
+	}
+
+	switch len(matching) {
+	case 0:
+		return Entry{}, ErrNoMatch
+	case 1:
+		return matching[0], nil
+	default:
+		return Entry{}, fmt.Errorf("%w: %d selections match, use an index or narrow the filters", ErrAmbiguous, len(matching))
+	}
+}
@@ -0,0 +1,92 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_select(t *testing.T) {
+	s := NewSelections()
+	for _, content := range []string{"alpha", "beta", "gamma", "delta"} {
+		s.Add(Selection{
+			Selection: xclip.Selection{
+				Content: []byte(content),
+				Target:  xclip.ValidTargetUTF8_STRING,
+			},
+		})
+	}
+	_, err := s.Promote(s.Entries()[3].ID())
+	require.NoError(t, err)
+	_, err = s.Promote(s.Entries()[2].ID())
+	require.NoError(t, err)
+	// List order: delta (last), gamma (important), alpha (important), beta
+
+	index := func(i int) *int { return &i }
+	tests := []struct {
+		name     string
+		selector Selector
+		want     string
+		err      error
+	}{
+		{name: "index", selector: Selector{Index: index(0)}, want: "delta"},
+		{name: "index among category", selector: Selector{Index: index(1), Category: SelectionRetentionTypeImportant}, want: "alpha"},
+		{name: "single match", selector: Selector{Match: regexp.MustCompile("^g")}, want: "gamma"},
+		{name: "ambiguous", selector: Selector{Match: regexp.MustCompile("a$")}, err: ErrAmbiguous},
+		{name: "ambiguous resolved by index", selector: Selector{Index: index(3), Match: regexp.MustCompile("a$")}, want: "beta"},
+		{name: "no match", selector: Selector{Target: xclip.ValidTargetImagePng}, err: ErrNoMatch},
+		{name: "out of range", selector: Selector{Index: index(4)}, err: ErrOutOfRange},
+		{name: "negative index", selector: Selector{Index: index(-1)}, err: ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := s.Select(tt.selector)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(entry.Content))
+		})
+	}
+}
+
+func TestSet_ClearSelected(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	s := NewSelections()
+	add := func(content string, age time.Duration) {
+		s.Add(Selection{
+			Selection:  xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING},
+			CapturedAt: clock.Add(-age),
+		})
+	}
+	add("old link https://example.com", 10*24*time.Hour)
+	add("old note", 10*24*time.Hour)
+	add(strings.Repeat("large", 1000), time.Hour)
+	add("new link https://example.com", time.Hour)
+	_, err := s.Pin(s.Entries()[3].ID())
+	require.NoError(t, err)
+
+	olderThanWeek := Selector{OlderThan: 7 * 24 * time.Hour}
+	dryRun := s.ClearSelected(olderThanWeek, SelectionRetentionTypeAll, ClearOptionDryRun(true))
+	assert.Equal(t, []string{"old note"}, contents(dryRun), "pinned selections are kept")
+	assert.Len(t, s.Entries(), 4)
+	assert.Empty(t, s.TrashBatches())
+
+	links := Selector{Match: regexp.MustCompile("https://"), OlderThan: 7 * 24 * time.Hour}
+	removed := s.ClearSelected(links, SelectionRetentionTypePinned)
+	assert.Equal(t, []string{"old link https://example.com"}, contents(removed))
+
+	removed = s.ClearSelected(Selector{LargerThan: 1000}, SelectionRetentionTypeAll)
+	require.Len(t, removed, 1)
+	assert.Len(t, removed[0].Content, 5000)
+	assert.Len(t, s.TrashBatches(), 2)
+}
@@ -0,0 +1,136 @@
+package selections
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidName is returned for tags and collection names that can't be written in a filter
+var ErrInvalidName = errors.New("invalid name")
+
+// ValidName checks a tag or collection name, it must not be empty nor have spaces or quotes
+func ValidName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidName)
+	}
+	if strings.ContainsFunc(name, func(r rune) bool { return unicode.IsSpace(r) || r == '"' }) {
+		return fmt.Errorf("%w: %q has spaces or quotes", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Collected reports whether the selection belongs to a collection, collected selections are never evicted
+func (s *Selection) Collected() bool {
+	return len(s.Collections) > 0
+}
+
+// Tag adds the tags to the selection with the given ID
+func (s *Set) Tag(id string, tags ...string) (Selection, error) {
+	return s.update(id, func(sel *Selection) {
+		sel.Tags = addNames(sel.Tags, tags)
+	})
+}
+
+// Untag removes the tags from the selection with the given ID
+func (s *Set) Untag(id string, tags ...string) (Selection, error) {
+	return s.update(id, func(sel *Selection) {
+		sel.Tags = removeNames(sel.Tags, tags)
+	})
+}
+
+// Collect adds the selection with the given ID to the named collections
+func (s *Set) Collect(id string, names ...string) (Selection, error) {
+	return s.update(id, func(sel *Selection) {
+		sel.Collections = addNames(sel.Collections, names)
+	})
+}
+
+// Uncollect removes the selection with the given ID from the named collections.
+// It may be evicted right away if it is no longer in any collection.
+func (s *Set) Uncollect(id string, names ...string) (Selection, error) {
+	return s.update(id, func(sel *Selection) {
+		sel.Collections = removeNames(sel.Collections, names)
+	})
+}
+
+// SetLabel replaces the label of the selection with the given ID, an empty label removes it
+func (s *Set) SetLabel(id string, label string) (Selection, error) {
+	return s.update(id, func(sel *Selection) {
+		sel.Label = label
+	})
+}
+
+// Tags counts the selections with each tag
+func (s *Set) Tags() map[string]int {
+	return s.count(func(sel Selection) []string { return sel.Tags })
+}
+
+// Collections counts the selections in each collection
+func (s *Set) Collections() map[string]int {
+	return s.count(func(sel Selection) []string { return sel.Collections })
+}
+
+func (s *Set) count(names func(Selection) []string) map[string]int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	counts := map[string]int{}
+	for _, entry := range s.entries() {
+		for _, name := range names(entry.Selection) {
+			counts[name]++
+		}
+	}
+	return counts
+}
+
+// update changes the selection with the given ID in its category and as the last selection
+func (s *Set) update(id string, change func(*Selection)) (Selection, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var updated *Selection
+	for _, category := range categories {
+		list := *s.list(category)
+		for i := range list {
+			if list[i].ID() == id {
+				change(&list[i])
+				updated = &list[i]
+			}
+		}
+	}
+	if s.Last != nil && s.Last.ID() == id {
+		if updated != nil {
+			// Keep the last selection in sync with the listed one
+			last := *updated
+			s.Last = &last
+		} else {
+			change(s.Last)
+			updated = s.Last
+		}
+	}
+	if updated == nil {
+		return Selection{}, ErrNoMatch
+	}
+	sel := *updated
+	s.truncate()
+	return sel, nil
+}
+
+func addNames(names []string, added []string) []string {
+	names = slices.Clone(names)
+	for _, name := range added {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func removeNames(names []string, removed []string) []string {
+	return slices.DeleteFunc(slices.Clone(names), func(name string) bool {
+		return slices.Contains(removed, name)
+	})
+}
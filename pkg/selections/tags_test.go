@@ -0,0 +1,97 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_tags(t *testing.T) {
+	s := NewSelections()
+	add := func(content string) string {
+		sel := Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}}
+		s.Add(sel)
+		return sel.ID()
+	}
+	snippet := add("kubectl get pods")
+	add("other")
+
+	sel, err := s.Tag(snippet, "k8s", "work", "k8s")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k8s", "work"}, sel.Tags)
+	_, err = s.Untag(snippet, "work")
+	require.NoError(t, err)
+	_, err = s.Tag("unknown", "work")
+	assert.ErrorIs(t, err, ErrNoMatch)
+
+	// Capturing the content again keeps its tags
+	add("kubectl get pods")
+	assert.Equal(t, []string{"k8s"}, s.Last.Tags)
+	assert.Equal(t, map[string]int{"k8s": 1}, s.Tags())
+
+	assert.NoError(t, ValidName("work"))
+	assert.ErrorIs(t, ValidName("two words"), ErrInvalidName)
+	assert.ErrorIs(t, ValidName(""), ErrInvalidName)
+}
+
+func TestSet_collections_are_not_evicted(t *testing.T) {
+	s := NewSelections()
+	s.Options.MaxEphemeralElements = 2
+	s.Options.Budget.Ephemeral = 20
+	add := func(content string) string {
+		sel := Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}}
+		s.Add(sel)
+		return sel.ID()
+	}
+	kept := add("collected snippet")
+	_, err := s.Collect(kept, "snippets")
+	require.NoError(t, err)
+	add("a")
+	add("b")
+	add("c")
+
+	assert.Equal(t, []string{"collected snippet", "b", "c"}, selectionContents(s.Ephemeral))
+	assert.Equal(t, map[string]int{"snippets": 1}, s.Collections())
+
+	// Once out of every collection the usual limits apply
+	_, err = s.Uncollect(kept, "snippets")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, selectionContents(s.Ephemeral))
+}
+
+func TestSet_collections_are_not_dropped_when_contained(t *testing.T) {
+	s := NewSelections()
+	evicted := []Eviction{}
+	s.OnEvict(func(e []Eviction) { evicted = append(evicted, e...) })
+	add := func(content string) string {
+		sel := Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}}
+		s.Add(sel)
+		return sel.ID()
+	}
+	foo := add("foo")
+	_, err := s.Collect(foo, "snippets")
+	require.NoError(t, err)
+	add("bar")
+	add("other")
+	add("foobar")
+
+	_, found := s.FindID(foo)
+	assert.True(t, found)
+	assert.Equal(t, []string{"foo", "other", "foobar"}, selectionContents(s.Ephemeral))
+
+	// Contained selections that are not collected are dropped and reported
+	require.Len(t, evicted, 1)
+	assert.Equal(t, "bar", evicted[0].String())
+	assert.Equal(t, "contained in a new selection", evicted[0].Reason)
+	assert.True(t, evicted[0].Contained)
+}
+
+func selectionContents(list []Selection) []string {
+	result := []string{}
+	for _, sel := range list {
+		result = append(result, sel.String())
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package selections
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ErrTrashEmpty is returned when undoing a clear with nothing in the trash
+var ErrTrashEmpty = errors.New("the trash is empty")
+
+// TrashedEntry is a cleared selection and its position in its category, used to put it back in place
+type TrashedEntry struct {
+	Entry
+	Position int
+}
+
+// TrashBatch holds the selections removed by a single clear
+type TrashBatch struct {
+	ID        int
+	ClearedAt time.Time
+	Entries   []TrashedEntry
+}
+
+type clearOptions struct {
+	permanent bool
+	dryRun    bool
+}
+
+type ClearOption func(*clearOptions)
+
+func newClearOptions(opts []ClearOption) clearOptions {
+	options := clearOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// ClearOptionPermanent removes the selections for good instead of moving them to the trash
+func ClearOptionPermanent(permanent bool) ClearOption {
+	return func(o *clearOptions) {
+		o.permanent = permanent
+	}
+}
+
+// ClearOptionDryRun returns the selections that would be removed without removing them
+func ClearOptionDryRun(dryRun bool) ClearOption {
+	return func(o *clearOptions) {
+		o.dryRun = dryRun
+	}
+}
+
+// trash keeps the removed selections as a new batch, the caller must hold the lock.
+// Nothing is kept if the clear is permanent or the trash is disabled.
+func (s *Set) trash(removed []TrashedEntry, options clearOptions) {
+	if len(removed) == 0 || options.permanent || s.Options.TrashRetention <= 0 {
+		return
+	}
+
+	id := 1
+	if len(s.Trash) > 0 {
+		id = s.Trash[len(s.Trash)-1].ID + 1
+	}
+	s.Trash = append(s.Trash, TrashBatch{
+		ID:        id,
+		ClearedAt: now(),
+		Entries:   removed,
+	})
+	slog.Debug("Moved selections to the trash", "batch", id, "count", len(removed))
+}
+
+// TrashBatches returns the batches in the trash, the most recent first
+func (s *Set) TrashBatches() []TrashBatch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batches := slices.Clone(s.Trash)
+	slices.Reverse(batches)
+	return batches
+}
+
+// Undo puts back the selections removed by the most recent clear
+func (s *Set) Undo() (TrashBatch, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.Trash) == 0 {
+		return TrashBatch{}, ErrTrashEmpty
+	}
+	return s.restoreBatch(len(s.Trash) - 1), nil
+}
+
+// RestoreTrash puts back the selections of the batch with the given ID
+func (s *Set) RestoreTrash(id int) (TrashBatch, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := slices.IndexFunc(s.Trash, func(batch TrashBatch) bool {
+		return batch.ID == id
+	})
+	if i < 0 {
+		return TrashBatch{}, ErrNoMatch
+	}
+	return s.restoreBatch(i), nil
+}
+
+// restoreBatch puts the selections back where they were and removes the batch from the trash.
+// Selections stored again since they were cleared are left as they are. The caller must hold the lock.
+func (s *Set) restoreBatch(i int) TrashBatch {
+	batch := s.Trash[i]
+	s.Trash = slices.Delete(s.Trash, i, i+1)
+
+	entries := slices.Clone(batch.Entries)
+	slices.SortStableFunc(entries, func(a, b TrashedEntry) int {
+		return a.Position - b.Position
+	})
+	for _, entry := range entries {
+		if _, found := s.lookup(entry.Equal); found {
+			continue
+		}
+		list := s.list(entry.Category)
+		if list == nil {
+			continue
+		}
+		*list = slices.Insert(*list, min(entry.Position, len(*list)), entry.Selection)
+	}
+	s.truncate()
+	slog.Info("Restored selections from the trash", "batch", batch.ID, "count", len(batch.Entries))
+	return batch
+}
+
+// EmptyTrash removes every batch from the trash and returns them
+func (s *Set) EmptyTrash() []TrashBatch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	removed := s.Trash
+	s.Trash = []TrashBatch{}
+	return removed
+}
+
+// PurgeTrash removes the batches older than the trash retention and returns them
+func (s *Set) PurgeTrash(at time.Time) []TrashBatch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	kept := []TrashBatch{}
+	removed := []TrashBatch{}
+	for _, batch := range s.Trash {
+		if s.Options.TrashRetention > 0 && at.Sub(batch.ClearedAt) < s.Options.TrashRetention {
+			kept = append(kept, batch)
+		} else {
+			removed = append(removed, batch)
+		}
+	}
+	s.Trash = kept
+	return removed
+}
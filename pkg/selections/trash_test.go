@@ -0,0 +1,65 @@
+package selections
+
+import (
+	"blueclip/pkg/xclip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_trash(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	s := NewSelections()
+	for _, content := range []string{"alpha", "beta", "gamma", "delta"} {
+		_, err := s.Add(Selection{Selection: xclip.Selection{Content: []byte(content), Target: xclip.ValidTargetUTF8_STRING}})
+		require.NoError(t, err)
+	}
+	_, err := s.Promote(s.Entries()[1].ID())
+	require.NoError(t, err)
+	before := contents(s.Entries())
+
+	removed := s.ClearLines([][]byte{[]byte("beta"), []byte("gamma")}, SelectionRetentionTypeAll)
+	assert.Len(t, removed, 2)
+	s.Clear([]byte("alpha"), SelectionRetentionTypeAll, ClearOptionPermanent(true))
+
+	batches := s.TrashBatches()
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches[0].ID)
+	assert.Len(t, batches[0].Entries, 2)
+
+	clock = clock.Add(time.Hour)
+	s.ClearAll(SelectionRetentionTypeAll)
+	assert.Empty(t, s.Ephemeral)
+	require.Len(t, s.TrashBatches(), 2)
+	assert.Equal(t, 2, s.TrashBatches()[0].ID)
+
+	batch, err := s.Undo()
+	require.NoError(t, err)
+	assert.Equal(t, 2, batch.ID)
+	batch, err = s.RestoreTrash(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, batch.ID)
+	_, err = s.Undo()
+	assert.ErrorIs(t, err, ErrTrashEmpty)
+
+	// Everything is back in place except the permanently cleared selection
+	assert.Equal(t, []string{"delta", "gamma", "beta"}, contents(s.Entries()))
+	assert.Equal(t, []string{"delta", "gamma", "beta", "alpha"}, before)
+
+	s.ClearAll(SelectionRetentionTypeAll)
+	clock = clock.Add(s.Options.TrashRetention)
+	assert.Len(t, s.PurgeTrash(clock.Add(-time.Second)), 0)
+	assert.Len(t, s.PurgeTrash(clock), 1)
+	assert.Empty(t, s.TrashBatches())
+
+	// Without retention the trash is disabled
+	s.Options.TrashRetention = 0
+	s.Add(Selection{Selection: xclip.Selection{Content: []byte("epsilon")}})
+	s.ClearAll(SelectionRetentionTypeAll)
+	assert.Empty(t, s.TrashBatches())
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"blueclip/pkg/hooks"
+	"blueclip/pkg/logging"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxAddSize is the largest content accepted by add, larger content is truncated
+const MaxAddSize = 64 << 20
+
+// TruncatedHeader is set in the add response when the content was truncated
+const TruncatedHeader = "Blueclip-Truncated"
+
+// AddTargets are the targets accepted when adding content
+var AddTargets = []xclip.ValidTarget{
+	xclip.ValidTargetUTF8_STRING,
+	xclip.ValidTargetSTRING,
+	xclip.ValidTargetTEXT,
+	xclip.ValidTargetTextPlain,
+	xclip.ValidTargetTextPlainUTF8,
+	xclip.ValidTargetTextUriList,
+	xclip.ValidTargetxSpecialGnomeCopiedFiles,
+	xclip.ValidTargetImagePng,
+}
+
+// DetectTarget guesses the target of content added without one
+func DetectTarget(content []byte) (xclip.ValidTarget, error) {
+	if http.DetectContentType(content) == "image/png" {
+		return xclip.ValidTargetImagePng, nil
+	}
+	if utf8.Valid(content) {
+		return xclip.ValidTargetUTF8_STRING, nil
+	}
+	return xclip.ValidTargetUnknown, fmt.Errorf("unable to detect the target of binary content, set it explicitly")
+}
+
+// HandleAdd stores the request body as a new selection and optionally copies it
+// to the clipboard selections
+func (s *Service) HandleAdd(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	maxSize := int64(MaxAddSize)
+	if value := query.Get("max-size"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || size <= 0 {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "invalid max-size %s", value)
+			return
+		}
+		maxSize = min(maxSize, size)
+	}
+
+	// Content may be streamed by long running commands, don't apply the server timeouts
+	controller := http.NewResponseController(resp)
+	controller.SetReadDeadline(time.Time{})
+	controller.SetWriteDeadline(time.Time{})
+
+	content, err := io.ReadAll(io.LimitReader(req.Body, maxSize+1))
+	if err != nil {
+		slog.Warn("Failed to read content", "error", err)
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("failed to read content"))
+		return
+	}
+	truncated := int64(len(content)) > maxSize
+	if truncated {
+		// Keep reading so the sender isn't blocked, the rest is dropped
+		dropped, _ := io.Copy(io.Discard, req.Body)
+		slog.Info("Truncating added content", "max", maxSize, "dropped", dropped+int64(len(content))-maxSize)
+		content = truncateText(content[:maxSize])
+	}
+	if len(content) == 0 {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("empty content"))
+		return
+	}
+
+	target := xclip.ValidTarget(query.Get("target"))
+	if target == xclip.ValidTargetUnknown {
+		target, err = DetectTarget(content)
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			resp.Write([]byte(err.Error()))
+			return
+		}
+	} else if !validAddTarget(target) {
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(resp, "invalid target %s, allowed targets are: %v", target, AddTargets)
+		return
+	}
+
+	if truncated && target == xclip.ValidTargetImagePng {
+		resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		fmt.Fprintf(resp, "image is larger than %d bytes", maxSize)
+		return
+	}
+
+	category := selections.SelectionRetentionType(query.Get("category"))
+	if category == "" {
+		category = selections.SelectionRetentionTypeEphemeral
+	}
+
+	clipboardSelections := query["clipboard-selection"]
+	clipboard := xclip.ClipboardSelection("")
+	if len(clipboardSelections) > 0 {
+		clipboard = xclip.ClipboardSelection(clipboardSelections[0])
+	}
+	sel := selections.Selection{
+		Selection:  xclip.NewSelection(content, target, clipboard),
+		CapturedAt: time.Now(),
+		Label:      query.Get("label"),
+	}
+
+	s.lock.Lock()
+	err = s.selections.Insert(sel, category)
+	if err != nil {
+		s.lock.Unlock()
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	if len(clipboardSelections) > 0 {
+		// The watchers will see the content, don't capture it again
+		s.selections.SetLast(sel)
+	}
+	s.applyPolicy(req.Context())
+	s.save()
+	s.lock.Unlock()
+	s.archivePending()
+	slog.Info("Added selection", "category", category, "target", target, logging.Content("content", content))
+
+	copied := []xclip.ClipboardSelection{}
+	for _, clipboardSelection := range clipboardSelections {
+		err := s.cli.Copy(
+			req.Context(),
+			bytes.NewReader(content),
+			xclip.CopyOptionSelection(xclip.ClipboardSelection(clipboardSelection)),
+			xclip.CopyOptionWithTarget(target),
+		)
+		if err != nil {
+			slog.Error("Failed to copy selection", "error", err)
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "failed to copy selection: %v", err)
+			return
+		}
+		s.metrics.copies.Inc(clipboardSelection, string(target))
+		copied = append(copied, xclip.ClipboardSelection(clipboardSelection))
+	}
+
+	s.hooks.Fire(req.Context(), hooks.Payload{
+		Event:      hooks.EventCapture,
+		Entry:      selections.Entry{Selection: sel, Category: category},
+		Clipboards: copied,
+	})
+
+	if truncated {
+		resp.Header().Set(TruncatedHeader, "true")
+	}
+	resp.WriteHeader(http.StatusOK)
+	fmt.Fprintln(resp, sel.ID())
+}
+
+// truncateText drops an incomplete rune left at the end by the truncation
+func truncateText(content []byte) []byte {
+	for i := 1; i <= utf8.UTFMax && i <= len(content); i++ {
+		start := len(content) - i
+		if !utf8.RuneStart(content[start]) {
+			continue
+		}
+		if !utf8.FullRune(content[start:]) {
+			return content[:start]
+		}
+		return content
+	}
+	return content
+}
+
+func validAddTarget(target xclip.ValidTarget) bool {
+	for _, t := range AddTargets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
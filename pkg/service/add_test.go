@@ -0,0 +1,64 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTarget(t *testing.T) {
+	img := &bytes.Buffer{}
+	require.NoError(t, png.Encode(img, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+
+	target, err := DetectTarget(img.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, xclip.ValidTargetImagePng, target)
+
+	target, err = DetectTarget([]byte("año"))
+	require.NoError(t, err)
+	assert.Equal(t, xclip.ValidTargetUTF8_STRING, target)
+
+	_, err = DetectTarget([]byte{0xff, 0xfe, 0x00})
+	assert.Error(t, err)
+}
+
+func TestHandleAdd(t *testing.T) {
+	s := newTestService(t)
+
+	rec := do(s.HandleAdd, http.MethodPost, "/add?category=important", "hello")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	require.Len(t, s.selections.Important, 1)
+	assert.Equal(t, xclip.ValidTargetUTF8_STRING, s.selections.Important[0].Target)
+	assert.Equal(t, s.selections.Important[0].ID()+"\n", rec.Body.String())
+	assert.Nil(t, s.selections.Last, "content not copied to a clipboard selection is not the last selection")
+
+	stored := selections.NewSelections()
+	require.NoError(t, s.db.Load(stored))
+	assert.Len(t, stored.Important, 1)
+
+	rec = do(s.HandleAdd, http.MethodPost, "/add?target=nope", "hello")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(s.HandleAdd, http.MethodPost, "/add?category=last", "hello")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleAdd_truncates_content(t *testing.T) {
+	s := newTestService(t)
+
+	rec := do(s.HandleAdd, http.MethodPost, "/add?max-size=7&label=test", "ññññññ")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(TruncatedHeader))
+
+	require.Len(t, s.selections.Ephemeral, 1)
+	assert.Equal(t, "ñññ", string(s.selections.Ephemeral[0].Content), "incomplete runes are dropped")
+	assert.Equal(t, "test", s.selections.Ephemeral[0].Label)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"blueclip/pkg/archive"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/logging"
+	"blueclip/pkg/search"
+	"blueclip/pkg/selections"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// archiveEvicted queues the dropped selections for the archive, if enabled.
+// It may be called with the set locked, archivePending writes them once the locks are released.
+// Selections contained in a new one are not archived, their content is still in the history.
+func (s *Service) archiveEvicted(evicted []selections.Eviction) {
+	if s.archive == nil {
+		return
+	}
+	now := time.Now()
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+	for _, e := range evicted {
+		if e.Contained {
+			continue
+		}
+		s.pending = append(s.pending, archive.Record{
+			Selection:  e.Selection,
+			Category:   e.Category,
+			ArchivedAt: now,
+			Reason:     e.Reason,
+		})
+	}
+}
+
+// archivePending appends the queued selections to the archive as a single batch,
+// the caller must not hold the service lock
+func (s *Service) archivePending() {
+	s.pendingLock.Lock()
+	records := s.pending
+	s.pending = nil
+	s.pendingLock.Unlock()
+	if len(records) == 0 {
+		return
+	}
+	err := s.archive.Append(records)
+	if err != nil {
+		slog.Error("Failed to archive selections", "count", len(records), "error", err)
+		return
+	}
+	slog.Debug("Archived selections", "count", len(records))
+}
+
+// compactArchive applies the archive limits, if enabled
+func (s *Service) compactArchive() {
+	if s.archive == nil {
+		return
+	}
+	dropped, err := s.archive.Compact(time.Now())
+	if err != nil {
+		slog.Error("Failed to compact archive", "path", s.archive.Path, "error", err)
+		return
+	}
+	if dropped > 0 {
+		slog.Info("Compacted archive", "path", s.archive.Path, "dropped", dropped)
+	}
+}
+
+// HandleSearch returns every entry matching the query and the category, target and match filters
+// as a SearchResult array. The query is matched against the whole content with the mode, substring by default.
+// The best matches come first and the index of every entry is its position in the list with the same order.
+func (s *Service) HandleSearch(resp http.ResponseWriter, req *http.Request) {
+	selector, matcher, err := searchFromQuery(req.URL.Query())
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	entries, err := s.selections.Ranked(selector.Order, time.Now())
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	found := []SearchResult{}
+	for i, entry := range entries {
+		if !selector.Matches(entry) {
+			continue
+		}
+		listEntry := listing.NewEntry(i, entry)
+		match, ok := matchEntry(matcher, listEntry)
+		if ok {
+			found = append(found, SearchResult{Entry: listEntry, SearchMatch: match})
+		}
+	}
+	// Stable so equally good matches keep the list order
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Score > found[j].Score
+	})
+	writeJSON(resp, found)
+}
+
+// HandleArchiveSearch is like HandleSearch for the archive, equally good matches are the most recently archived first
+func (s *Service) HandleArchiveSearch(resp http.ResponseWriter, req *http.Request) {
+	if !s.archiveEnabled(resp) {
+		return
+	}
+	selector, matcher, err := searchFromQuery(req.URL.Query())
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	records, err := s.archive.Search(func(record archive.Record) bool {
+		return selector.Matches(selections.Entry{Selection: record.Selection, Category: record.Category})
+	})
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	found := []ArchiveEntry{}
+	for i, record := range records {
+		entry := NewArchiveEntry(i, record)
+		match, ok := matchEntry(matcher, entry.Entry)
+		if ok {
+			entry.SearchMatch = match
+			found = append(found, entry)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Score > found[j].Score
+	})
+	writeJSON(resp, found)
+}
+
+// searchFromQuery reads the selector filters and the query with its mode
+func searchFromQuery(query url.Values) (selections.Selector, *search.Matcher, error) {
+	selector, _, err := selectorFromQuery(query)
+	if err != nil {
+		return selector, nil, err
+	}
+	mode, err := search.ParseMode(query.Get("mode"))
+	if err != nil {
+		return selector, nil, err
+	}
+	matcher, err := search.New(mode, query.Get("query"))
+	if err != nil {
+		return selector, nil, err
+	}
+	return selector, matcher, nil
+}
+
+// matchEntry matches the content and the label of the entry and keeps the best match
+func matchEntry(matcher *search.Matcher, entry listing.Entry) (SearchMatch, bool) {
+	result, ok := matcher.Match(entry.Line)
+	if entry.Label != "" {
+		label, found := matcher.Match(entry.Label)
+		if found && (!ok || label.Score > result.Score) {
+			return SearchMatch{Score: label.Score, LabelOffsets: label.Spans}, true
+		}
+	}
+	if !ok {
+		return SearchMatch{}, false
+	}
+	return SearchMatch{Score: result.Score, Offsets: result.Spans}, true
+}
+
+// HandleArchiveRestore stores an archived selection back in the history. It goes back to the
+// category it was dropped from unless the category is given.
+func (s *Service) HandleArchiveRestore(resp http.ResponseWriter, req *http.Request) {
+	if !s.archiveEnabled(resp) {
+		return
+	}
+	query := req.URL.Query()
+	id := query.Get("id")
+	if id == "" {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("missing id"))
+		return
+	}
+
+	record, found, err := s.archive.Find(id)
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	if !found {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "no archived selection found with id %s", id)
+		return
+	}
+
+	category := selections.SelectionRetentionType(query.Get("category"))
+	if category == "" {
+		category = record.Category
+	}
+
+	s.lock.Lock()
+	err = s.selections.Insert(record.Selection, category)
+	if err != nil {
+		s.lock.Unlock()
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	s.save()
+	s.lock.Unlock()
+	s.archivePending()
+	slog.Info("Restored archived selection", "category", category, logging.Content("selection", record.Content))
+
+	resp.WriteHeader(http.StatusOK)
+	fmt.Fprintln(resp, id)
+}
+
+func (s *Service) archiveEnabled(resp http.ResponseWriter) bool {
+	if s.archive == nil {
+		resp.WriteHeader(http.StatusNotFound)
+		resp.Write([]byte("the archive is disabled"))
+		return false
+	}
+	return true
+}
+
+func writeJSON(resp http.ResponseWriter, v any) {
+	resp.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(resp).Encode(v)
+	if err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"blueclip/pkg/config"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/search"
+	"blueclip/pkg/xclip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleArchive(t *testing.T) {
+	s := newTestServiceWithConfig(t, &config.Config{
+		Retention: config.RetentionConfig{MaxEphemeral: 1},
+	})
+	assert.Equal(t, filepath.Join(filepath.Dir(s.db.Path), "history.archive"), s.archive.Path)
+
+	for _, content := range []string{"first", "second"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	search := func(path string, v any) {
+		handler := s.HandleSearch
+		if strings.HasPrefix(path, "/archive") {
+			handler = s.HandleArchiveSearch
+		}
+		rec := do(handler, http.MethodGet, path, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(v))
+	}
+
+	archived := []ArchiveEntry{}
+	search("/archive/search?match=^f", &archived)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "first", archived[0].Line)
+	assert.Equal(t, "ephemeral limit", archived[0].Reason)
+
+	found := []listing.Entry{}
+	search("/search?match=^f", &found)
+	assert.Empty(t, found)
+
+	rec := do(s.HandleArchiveRestore, http.MethodPost, "/archive/restore?category=important&id="+archived[0].ID, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	search("/search?match=^f", &found)
+	require.Len(t, found, 1)
+	assert.Equal(t, "important", string(found[0].Category))
+
+	rec = do(s.HandleArchiveRestore, http.MethodPost, "/archive/restore?id=unknown", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleClipboardChange_archive(t *testing.T) {
+	s := newTestServiceWithConfig(t, &config.Config{
+		Retention: config.RetentionConfig{MaxEphemeral: 2},
+	})
+
+	for _, content := range []string{"foo", "other", "foobar", "last"} {
+		s.handleClipboardChange(context.Background(), xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard))
+	}
+
+	// foo is still in foobar, only the selection dropped by the limit is archived
+	records, err := s.archive.Records()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "other", records[0].String())
+}
+
+func TestHandleSearch_query(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"port 80\nport 443", "no match", "first line\nthe port"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	find := func(query string) []SearchResult {
+		rec := do(s.HandleSearch, http.MethodGet, "/search?"+query, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		found := []SearchResult{}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&found))
+		return found
+	}
+
+	found := find("query=port")
+	require.Len(t, found, 2)
+	assert.Equal(t, "port 80\nport 443", found[0].Line, "more matches rank first")
+	assert.Equal(t, []search.Span{{0, 4}, {8, 12}}, found[0].Offsets)
+	assert.Equal(t, []search.Span{{15, 19}}, found[1].Offsets, "matches past the first line")
+
+	found = find("query=fln&mode=fuzzy")
+	require.Len(t, found, 1)
+	assert.Equal(t, 0, found[0].Index)
+
+	rec := do(s.HandleSearch, http.MethodGet, "/search?query=(&mode=regex", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
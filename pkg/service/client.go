@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -35,12 +36,147 @@ func NewClient(socket string) *Client {
 	return client
 }
 
-func (c *Client) List(ctx context.Context) (*http.Response, error) {
+// Selector picks an entry by its position in the list and filters, it is resolved by the server
+type Selector struct {
+	// Index among the matching entries, if nil the filters must match a single entry
+	Index    *int
+	Category string
+	Target   string
+	// Match is a regular expression matched against the content
+	Match string
+	// OlderThan is a duration such as 12h or 7d since the last capture
+	OlderThan string
+	// LargerThan is a size such as 1MB
+	LargerThan string
+	// Filter is a filter query such as `target:image age:<2h`
+	Filter string
+	// Order ranks the entries before applying the index, see ListWithOrder
+	Order string
+}
+
+func (s Selector) apply(req *http.Request) {
+	q := req.URL.Query()
+	if s.Index != nil {
+		q.Set("index", strconv.Itoa(*s.Index))
+	}
+	if s.Category != "" {
+		q.Set("category", s.Category)
+	}
+	if s.Target != "" {
+		q.Set("target", s.Target)
+	}
+	if s.Match != "" {
+		q.Set("match", s.Match)
+	}
+	if s.OlderThan != "" {
+		q.Set("older-than", s.OlderThan)
+	}
+	if s.LargerThan != "" {
+		q.Set("larger-than", s.LargerThan)
+	}
+	if s.Filter != "" {
+		q.Set("filter", s.Filter)
+	}
+	if s.Order != "" {
+		q.Set("order", s.Order)
+	}
+	req.URL.RawQuery = q.Encode()
+}
+
+type ListOption func(*http.Request)
+
+// ListWithFormat changes the list format, json returns a listing.Entry array
+func ListWithFormat(format string) ListOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("format", format)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// ListWithOrder ranks the entries with one of interleave, recent, frecency or size
+func ListWithOrder(order string) ListOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("order", order)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// ListWithFilter lists only the entries matching the filter query, such as `target:image age:<2h`
+func ListWithFilter(filter string) ListOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("filter", filter)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+func (c *Client) List(ctx context.Context, opts ...ListOption) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://blueclip/list", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) Status(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://blueclip/status", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+type LogOption func(*http.Request)
+
+func LogWithLevel(level string) LogOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("level", level)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+func LogWithContent(content bool) LogOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("content", strconv.FormatBool(content))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// Log reads the server log settings, or changes them if any option is given
+func (c *Client) Log(ctx context.Context, opts ...LogOption) (*http.Response, error) {
+	method := http.MethodGet
+	if len(opts) > 0 {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "http://blueclip/log", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
 	resp, err := c.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
@@ -68,6 +204,29 @@ func PrintWithDimensions(width, height int) PrintOption {
 	}
 }
 
+// PrintWithID selects the selection by ID instead of the line read from the input
+func PrintWithID(id string) PrintOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("id", id)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// PrintWithRegister prints the selection held by the register instead of the line read from the input
+func PrintWithRegister(name string) PrintOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("register", name)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// PrintWithSelector selects the selection with a selector instead of the line read from the input
+func PrintWithSelector(selector Selector) PrintOption {
+	return selector.apply
+}
+
 func (c *Client) Print(ctx context.Context, in io.Reader, opts ...PrintOption) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/print", in)
 	if err != nil {
@@ -97,6 +256,39 @@ func CopyWithClipboardSelection(selection []string) CopyOption {
 	}
 }
 
+// CopyWithID selects the selection by ID instead of the line read from the input
+func CopyWithID(id string) CopyOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("id", id)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// CopyWithKeepCategory chooses whether the selection stays in its category or is moved
+// to the important list, the server configuration decides if it is not set
+func CopyWithKeepCategory(keep bool) CopyOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("keep-category", strconv.FormatBool(keep))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// CopyWithRegister copies the selection held by the register instead of the line read from the input
+func CopyWithRegister(name string) CopyOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("register", name)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// CopyWithSelector selects the selection with a selector instead of the line read from the input
+func CopyWithSelector(selector Selector) CopyOption {
+	return selector.apply
+}
+
 func (c *Client) Copy(ctx context.Context, in io.Reader, opts ...CopyOption) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/copy", in)
 	if err != nil {
@@ -125,6 +317,49 @@ func ClearWithType(t string) ClearOption {
 	}
 }
 
+// ClearWithID selects the selection by ID instead of the lines read from the input
+func ClearWithID(id string) ClearOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("id", id)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// ClearWithSelector selects the selection with a selector instead of the lines read from the input
+func ClearWithSelector(selector Selector) ClearOption {
+	return selector.apply
+}
+
+// ClearWithAll clears every selection of the type instead of the lines read from the input.
+// With a selector, it clears every selection passing its filters and the index is not used.
+// The response is a ClearResult.
+func ClearWithAll() ClearOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("all", "true")
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// ClearWithDryRun only reports what a clear with all would remove
+func ClearWithDryRun(dryRun bool) ClearOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("dry-run", strconv.FormatBool(dryRun))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// ClearWithPermanent deletes the selections for good instead of moving them to the trash
+func ClearWithPermanent(permanent bool) ClearOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("permanent", strconv.FormatBool(permanent))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
 func (c *Client) Clear(ctx context.Context, in io.Reader, opts ...ClearOption) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/clear", in)
 	if err != nil {
@@ -142,3 +377,381 @@ func (c *Client) Clear(ctx context.Context, in io.Reader, opts ...ClearOption) (
 
 	return resp, nil
 }
+
+// MoveOption picks the selection moved by Promote, Demote, Pin and Unpin
+type MoveOption func(*http.Request)
+
+func MoveWithID(id string) MoveOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("id", id)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+func MoveWithSelector(selector Selector) MoveOption {
+	return selector.apply
+}
+
+// Promote moves the selection to the important list
+func (c *Client) Promote(ctx context.Context, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "promote", opts...)
+}
+
+// Demote moves the selection to the ephemeral list
+func (c *Client) Demote(ctx context.Context, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "demote", opts...)
+}
+
+// Pin moves the selection to the pinned list
+func (c *Client) Pin(ctx context.Context, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "pin", opts...)
+}
+
+// Unpin moves the pinned selection back to the important list
+func (c *Client) Unpin(ctx context.Context, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "unpin", opts...)
+}
+
+func (c *Client) move(ctx context.Context, action string, opts ...MoveOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/"+action, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s request: %v", action, err)
+	}
+
+	return resp, nil
+}
+
+type AddOption func(*http.Request)
+
+// AddWithTarget sets the target of the content, it is detected by the server if not set
+func AddWithTarget(target string) AddOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("target", target)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// AddWithCategory stores the content as important or ephemeral, the default
+func AddWithCategory(category string) AddOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("category", category)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// AddWithClipboardSelection also copies the content to the clipboard selections
+func AddWithClipboardSelection(selection []string) AddOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		for _, s := range selection {
+			q.Add("clipboard-selection", s)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// AddWithLabel describes where the content comes from
+func AddWithLabel(label string) AddOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("label", label)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// AddWithMaxSize truncates the content to size bytes
+func AddWithMaxSize(size int64) AddOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("max-size", strconv.FormatInt(size, 10))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+func (c *Client) Add(ctx context.Context, in io.Reader, opts ...AddOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/add", in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add request: %v", err)
+	}
+
+	return resp, nil
+}
+
+type SearchOption func(*http.Request)
+
+// SearchWithSelector filters the entries by category, target and match and ranks them by order,
+// the index is ignored
+func SearchWithSelector(selector Selector) SearchOption {
+	return selector.apply
+}
+
+// SearchWithQuery matches the query against the whole content, the best matches first
+func SearchWithQuery(query string) SearchOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("query", query)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// SearchWithMode matches the query as substring, regex or fuzzy, substring by default
+func SearchWithMode(mode string) SearchOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("mode", mode)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// Search returns the matching entries as a SearchResult array
+func (c *Client) Search(ctx context.Context, opts ...SearchOption) (*http.Response, error) {
+	return c.search(ctx, "http://blueclip/search", opts...)
+}
+
+// SearchArchive returns the matching archived entries as an ArchiveEntry array
+func (c *Client) SearchArchive(ctx context.Context, opts ...SearchOption) (*http.Response, error) {
+	return c.search(ctx, "http://blueclip/archive/search", opts...)
+}
+
+func (c *Client) search(ctx context.Context, url string, opts ...SearchOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+type RestoreOption func(*http.Request)
+
+// RestoreWithCategory restores the selection to the category instead of the one it was dropped from
+func RestoreWithCategory(category string) RestoreOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("category", category)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// RestoreArchived stores the archived selection with the given ID back in the history
+func (c *Client) RestoreArchived(ctx context.Context, id string, opts ...RestoreOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/archive/restore", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	q := req.URL.Query()
+	q.Set("id", id)
+	req.URL.RawQuery = q.Encode()
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+// Trash returns the batches in the trash as a TrashBatchEntry array
+func (c *Client) Trash(ctx context.Context) (*http.Response, error) {
+	return c.trash(ctx, http.MethodGet, "http://blueclip/trash")
+}
+
+// Undo restores the selections removed by the most recent clear
+func (c *Client) Undo(ctx context.Context) (*http.Response, error) {
+	return c.trash(ctx, http.MethodPost, "http://blueclip/undo")
+}
+
+// RestoreTrash restores the selections removed by the clear with the given batch ID
+func (c *Client) RestoreTrash(ctx context.Context, batch int) (*http.Response, error) {
+	return c.trash(ctx, http.MethodPost, "http://blueclip/trash/restore?batch="+strconv.Itoa(batch))
+}
+
+// EmptyTrash deletes every selection in the trash for good
+func (c *Client) EmptyTrash(ctx context.Context) (*http.Response, error) {
+	return c.trash(ctx, http.MethodPost, "http://blueclip/trash/empty")
+}
+
+func (c *Client) trash(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+// RevertOption picks the X selection and how far back Revert goes
+type RevertOption func(*http.Request)
+
+// RevertWithSelection reverts the X selection instead of the clipboard
+func RevertWithSelection(selection string) RevertOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("selection", selection)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// RevertWithSteps goes back the given number of owners instead of one
+func RevertWithSteps(steps int) RevertOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("steps", strconv.Itoa(steps))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// Revert owns the X selection again with its previous content and returns the content as listed
+func (c *Client) Revert(ctx context.Context, opts ...RevertOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/revert", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+// Label replaces the label of the selection picked by the options, an empty label removes it
+func (c *Client) Label(ctx context.Context, label string, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "label", append(opts, func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("label", label)
+		req.URL.RawQuery = q.Encode()
+	})...)
+}
+
+// Tag adds the tags to the selection picked by the options
+func (c *Client) Tag(ctx context.Context, tags []string, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "tag/add", append(opts, withNames("tag", tags))...)
+}
+
+// Untag removes the tags from the selection picked by the options
+func (c *Client) Untag(ctx context.Context, tags []string, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "tag/rm", append(opts, withNames("tag", tags))...)
+}
+
+// Tags returns how many selections have each tag as a JSON object
+func (c *Client) Tags(ctx context.Context) (*http.Response, error) {
+	return c.get(ctx, "http://blueclip/tags")
+}
+
+// Collect adds the selection picked by the options to the named collections
+func (c *Client) Collect(ctx context.Context, names []string, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "collection/add", append(opts, withNames("name", names))...)
+}
+
+// Uncollect removes the selection picked by the options from the named collections
+func (c *Client) Uncollect(ctx context.Context, names []string, opts ...MoveOption) (*http.Response, error) {
+	return c.move(ctx, "collection/rm", append(opts, withNames("name", names))...)
+}
+
+// Collections returns how many selections are in each collection as a JSON object
+func (c *Client) Collections(ctx context.Context) (*http.Response, error) {
+	return c.get(ctx, "http://blueclip/collections")
+}
+
+func withNames(param string, names []string) MoveOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		for _, name := range names {
+			q.Add(param, name)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
+
+// Registers returns the registers holding a selection as a RegisterEntry array
+func (c *Client) Registers(ctx context.Context) (*http.Response, error) {
+	return c.get(ctx, "http://blueclip/registers")
+}
+
+// SetRegister stores the content read from in in the register. Without content it stores the
+// selection picked by the options, the last selection by default. It returns the RegisterEntry.
+func (c *Client) SetRegister(ctx context.Context, name string, in io.Reader, opts ...MoveOption) (*http.Response, error) {
+	return c.register(ctx, "set", name, in, opts...)
+}
+
+// ClearRegister empties the register
+func (c *Client) ClearRegister(ctx context.Context, name string) (*http.Response, error) {
+	return c.register(ctx, "clear", name, nil)
+}
+
+func (c *Client) register(ctx context.Context, action string, name string, in io.Reader, opts ...MoveOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://blueclip/register/"+action+"?name="+url.QueryEscape(name), in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	return resp, nil
+}
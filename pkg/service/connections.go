@@ -1,19 +1,26 @@
 package service
 
 import (
+	"blueclip/pkg/filter"
+	"blueclip/pkg/hooks"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/logging"
 	"blueclip/pkg/selections"
 	"blueclip/pkg/xclip"
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image/png"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"time"
 
@@ -76,118 +83,447 @@ func (s *Service) HandleClear(resp http.ResponseWriter, req *http.Request) {
 	if typeString == "" {
 		typeString = "all"
 	}
+	opts := []selections.ClearOption{}
+	if value := query.Get("permanent"); value != "" {
+		permanent, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "invalid permanent %s", value)
+			return
+		}
+		opts = append(opts, selections.ClearOptionPermanent(permanent))
+	}
 
 	all := query.Get("all")
 	if all == "true" {
-		log.Printf("Clearing all selections of type: %s", typeString)
-		switch typ := selections.SelectionRetentionType(typeString); typ {
+		s.clearSelected(resp, req, typeString, opts)
+		return
+	}
+
+	id, ok := s.resolveID(resp, req)
+	if !ok {
+		return
+	}
+	if id != "" {
+		typ := selections.SelectionRetentionType(typeString)
+		switch typ {
 		case selections.SelectionRetentionTypeAll,
 			selections.SelectionRetentionTypeEphemeral,
-			selections.SelectionRetentionTypeImportant:
-			s.selections.ClearAll(typ)
+			selections.SelectionRetentionTypeImportant,
+			selections.SelectionRetentionTypePinned:
 		default:
 			resp.WriteHeader(http.StatusBadRequest)
-			resp.Write([]byte("invalid type, allowed types are: all, ephemeral, important"))
+			resp.Write([]byte("invalid type, allowed types are: all, ephemeral, important, pinned"))
 			return
 		}
+		slog.Info("Clearing selection", "id", id, "type", typeString)
+		removed := s.selections.ClearID(id, typ, opts...)
+		s.fireClear(req.Context(), removed)
+		if len(removed) == 0 {
+			// Like the moves, the all type refuses to clear a pinned selection
+			if selection, ok := s.selections.FindID(id); ok && s.selections.Category(selection) == selections.SelectionRetentionTypePinned {
+				resp.WriteHeader(http.StatusConflict)
+				resp.Write([]byte(selections.ErrPinned.Error()))
+				return
+			}
+			resp.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(resp, "no selection found with id %s", id)
+			return
+		}
+		s.persist()
+		resp.WriteHeader(http.StatusOK)
 		return
 	}
 
-	log.Printf("Clearing selections of type: %s", typeString)
+	slog.Info("Clearing selections", "type", typeString)
 	pattern, err := io.ReadAll(req.Body)
 	if err != nil {
-		log.Printf("Failed to read pattern: %v", err)
+		slog.Warn("Failed to read pattern", "error", err)
 		resp.WriteHeader(http.StatusBadRequest)
 		resp.Write([]byte("failed to read pattern"))
 		return
 	}
 
-	patterns := bytes.Split(pattern, []byte("\000"))
+	typ := selections.SelectionRetentionType(typeString)
+	switch typ {
+	case selections.SelectionRetentionTypeAll,
+		selections.SelectionRetentionTypeEphemeral,
+		selections.SelectionRetentionTypeImportant,
+		selections.SelectionRetentionTypePinned:
+	default:
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("invalid type, allowed types are: all, ephemeral, important, pinned"))
+		return
+	}
+
+	patterns := [][]byte{}
+	for _, pattern := range bytes.Split(pattern, []byte("\000")) {
+		if len(pattern) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+	// All the patterns are cleared at once so a single undo restores them
+	removed := s.selections.ClearLines(patterns, typ, opts...)
+	s.fireClear(req.Context(), removed)
+	if len(removed) > 0 {
+		s.persist()
+	}
 	for _, pattern := range patterns {
-		if len(pattern) == 0 {
-			continue
+		// Like the selections, content ending in a new line matches as is or without it
+		cleared := slices.ContainsFunc(removed, func(entry selections.Entry) bool {
+			clean := entry.Clean()
+			return bytes.Equal(clean, pattern) || bytes.Equal(clean, bytes.TrimSuffix(pattern, []byte("\n")))
+		})
+		if !cleared {
+			slog.Info("No match found for pattern", logging.Content("pattern", pattern))
+			resp.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(resp, "no match found for pattern \"%s\"", string(pattern))
+			return
 		}
-		switch typ := selections.SelectionRetentionType(typeString); typ {
-		case selections.SelectionRetentionTypeAll,
-			selections.SelectionRetentionTypeEphemeral,
-			selections.SelectionRetentionTypeImportant:
-			found := s.selections.Clear(pattern, typ)
-			if !found {
-				log.Printf("No match found for pattern: %s", string(pattern))
-				resp.WriteHeader(http.StatusNotFound)
-				fmt.Fprintf(resp, "no match found for pattern \"%s\"", string(pattern))
-				return
-			}
-		default:
+	}
+
+	resp.WriteHeader(http.StatusOK)
+}
+
+// clearSelected clears every selection of the type passing the selector filters, all of them without filters.
+// It writes a ClearResult, with the dry-run parameter nothing is removed.
+func (s *Service) clearSelected(resp http.ResponseWriter, req *http.Request, typeString string, opts []selections.ClearOption) {
+	query := req.URL.Query()
+	selector, _, err := selectorFromQuery(query)
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	typ := selections.SelectionRetentionType(typeString)
+	switch typ {
+	case selections.SelectionRetentionTypeAll,
+		selections.SelectionRetentionTypeEphemeral,
+		selections.SelectionRetentionTypeImportant,
+		selections.SelectionRetentionTypePinned:
+	default:
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("invalid type, allowed types are: all, ephemeral, important, pinned"))
+		return
+	}
+	dryRun := false
+	if value := query.Get("dry-run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
 			resp.WriteHeader(http.StatusBadRequest)
-			resp.Write([]byte("invalid type, allowed types are: all, ephemeral, important"))
+			fmt.Fprintf(resp, "invalid dry-run %s", value)
 			return
 		}
+		opts = append(opts, selections.ClearOptionDryRun(dryRun))
+	}
+
+	slog.Info("Clearing all selections", "type", typ, "dry-run", dryRun)
+	removed := s.selections.ClearSelected(selector, typ, opts...)
+	if !dryRun {
+		s.fireClear(req.Context(), removed)
+		s.persist()
+	}
+
+	result := ClearResult{
+		Count:   len(removed),
+		DryRun:  dryRun,
+		Entries: make([]listing.Entry, len(removed)),
+	}
+	for i, entry := range removed {
+		result.Entries[i] = listing.NewEntry(i, entry)
+	}
+	writeJSON(resp, result)
+}
+
+// HandlePromote moves a selection to the important list
+func (s *Service) HandlePromote(resp http.ResponseWriter, req *http.Request) {
+	s.handleMove(resp, req, "Promoted", s.selections.Promote)
+}
+
+// HandleDemote moves a selection to the ephemeral list
+func (s *Service) HandleDemote(resp http.ResponseWriter, req *http.Request) {
+	s.handleMove(resp, req, "Demoted", s.selections.Demote)
+}
+
+// HandlePin moves a selection to the pinned list
+func (s *Service) HandlePin(resp http.ResponseWriter, req *http.Request) {
+	s.handleMove(resp, req, "Pinned", s.selections.Pin)
+}
+
+// HandleUnpin moves a pinned selection back to the important list
+func (s *Service) HandleUnpin(resp http.ResponseWriter, req *http.Request) {
+	s.handleMove(resp, req, "Unpinned", s.selections.Unpin)
+}
+
+func (s *Service) handleMove(resp http.ResponseWriter, req *http.Request, action string, move func(id string) (selections.Selection, error)) {
+	id, ok := s.resolveID(resp, req)
+	if !ok {
+		return
+	}
+	if id == "" {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("missing id or selector"))
+		return
 	}
 
+	selection, err := move(id)
+	switch {
+	case errors.Is(err, selections.ErrNoMatch):
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "no selection found with id %s", id)
+		return
+	case err != nil:
+		resp.WriteHeader(http.StatusConflict)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	slog.Info(action+" selection", "id", id, logging.Content("selection", selection.Content))
+	s.persist()
 	resp.WriteHeader(http.StatusOK)
 }
 
-func (s *Service) HandleList(resp http.ResponseWriter, req *http.Request) {
-	log.Printf("Listing selections")
-	s.selections.List(resp)
+func (s *Service) fireClear(ctx context.Context, removed []selections.Entry) {
+	for _, entry := range removed {
+		s.hooks.Fire(ctx, hooks.Payload{
+			Event: hooks.EventClear,
+			Entry: entry,
+		})
+	}
 }
 
-func (s *Service) HandleCopy(resp http.ResponseWriter, req *http.Request) {
-	line, err := io.ReadAll(req.Body)
+func (s *Service) HandleList(resp http.ResponseWriter, req *http.Request) {
+	slog.Debug("Listing selections")
+	query := req.URL.Query()
+	order, err := selections.ParseOrder(query.Get("order"))
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	var f *filter.Filter
+	if value := query.Get("filter"); value != "" {
+		f, err = filter.Parse(value)
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			resp.Write([]byte(err.Error()))
+			return
+		}
+	}
+	now := time.Now()
+	entries, err := s.selections.Ranked(order, now)
 	if err != nil {
-		log.Printf("Failed to read line: %v", err)
+		resp.WriteHeader(http.StatusInternalServerError)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	// Filtered entries keep their index in the whole list
+	indexes := []int{}
+	filtered := []selections.Entry{}
+	for i, entry := range entries {
+		if f == nil || f.Matches(entry, now) {
+			indexes = append(indexes, i)
+			filtered = append(filtered, entry)
+		}
+	}
+
+	if query.Get("format") == "json" {
+		list := make([]listing.Entry, len(filtered))
+		for i, entry := range filtered {
+			list[i] = listing.NewEntry(indexes[i], entry)
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(resp).Encode(list)
+		if err != nil {
+			slog.Warn("Failed to write list", "error", err)
+		}
 		return
 	}
+	selections.WriteLines(resp, filtered)
+}
 
-	selection, ok := s.selections.Copy(line)
+func (s *Service) HandleCopy(resp http.ResponseWriter, req *http.Request) {
+	id, ok := s.resolveID(resp, req)
 	if !ok {
-		log.Printf("No match found for line: %s", line)
 		return
 	}
 
+	keepCategory := s.keepCategory
+	if value := req.URL.Query().Get("keep-category"); value != "" {
+		var err error
+		keepCategory, err = strconv.ParseBool(value)
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "invalid keep-category %s", value)
+			return
+		}
+	}
+	copyOption := selections.CopyOptionKeepCategory(keepCategory)
+
+	var selection selections.Selection
+	if name := req.URL.Query().Get("register"); name != "" {
+		// Registers are copied as they are, the history changes when the content is captured
+		selection, ok = s.register(resp, name)
+		if !ok {
+			return
+		}
+	} else if id != "" {
+		selection, ok = s.selections.CopyID(id, copyOption)
+		if !ok {
+			resp.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(resp, "no selection found with id %s", id)
+			return
+		}
+	} else {
+		line, err := io.ReadAll(req.Body)
+		if err != nil {
+			slog.Warn("Failed to read line", "error", err)
+			return
+		}
+
+		selection, ok = s.selections.Copy(line, copyOption)
+		if !ok {
+			// Not an error, the line is empty when the picker piping it was aborted
+			slog.Info("No match found for line", logging.Content("line", line))
+			return
+		}
+	}
+	s.lock.Lock()
+	s.applyPolicy(req.Context())
+	s.save()
+	s.lock.Unlock()
+	s.archivePending()
+
 	clipboardSelections := req.URL.Query()["clipboard-selection"]
-	log.Printf("Clipboard selections: %v", clipboardSelections)
+	slog.Debug("Copying to clipboard selections", "selections", clipboardSelections)
 
 	if len(clipboardSelections) == 0 {
 		clipboardSelections = []string{string(xclip.ClipboardSelectionClipboard)}
 	}
 
 	for _, clipboardSelection := range clipboardSelections {
-		log.Printf("Copying selection to clipboard: %s with target: %s", clipboardSelection, selection.Target)
-		err = xclip.Cli.Copy(
+		slog.Info("Copying selection to clipboard", "selection", clipboardSelection, "target", selection.Target, logging.Content("content", selection.Content))
+		err := s.cli.Copy(
 			req.Context(),
 			bytes.NewReader(selection.Content),
 			xclip.CopyOptionSelection(xclip.ClipboardSelection(clipboardSelection)),
 			xclip.CopyOptionWithTarget(selection.Target),
 		)
 		if err != nil {
-			log.Printf("Failed to copy selection: %v", err)
+			slog.Error("Failed to copy selection", "error", err)
 			resp.WriteHeader(http.StatusBadRequest)
 			resp.Write([]byte(fmt.Sprintf("failed to copy selection: %v", err)))
 			return
 		}
+		s.metrics.copies.Inc(clipboardSelection, string(selection.Target))
 	}
 
+	copied := make([]xclip.ClipboardSelection, len(clipboardSelections))
+	for i, c := range clipboardSelections {
+		copied[i] = xclip.ClipboardSelection(c)
+	}
+	s.hooks.Fire(req.Context(), hooks.Payload{
+		Event: hooks.EventCopy,
+		Entry: selections.Entry{
+			Selection: selection,
+			Category:  s.selections.Category(selection),
+		},
+		Clipboards: copied,
+	})
+
 	resp.WriteHeader(http.StatusOK)
 }
 
-func (s *Service) HandlePrint(resp http.ResponseWriter, req *http.Request) {
-	unindentFlag := req.URL.Query().Get("unindent")
-	log.Printf("Handle print with unindent flag: %s", unindentFlag)
+// HandleRevert owns the X selection given by the selection parameter, clipboard by default,
+// with the selection that owned it the given steps before, one by default
+func (s *Service) HandleRevert(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	clip := xclip.ClipboardSelectionClipboard
+	if value := query.Get("selection"); value != "" {
+		clip = xclip.ClipboardSelection(value)
+	}
+	steps := 1
+	if value := query.Get("steps"); value != "" {
+		var err error
+		steps, err = strconv.Atoi(value)
+		if err != nil || steps < 1 {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "invalid steps %s, it must be a positive number", value)
+			return
+		}
+	}
+	// Only the watched X selections have a history
+	switch clip {
+	case xclip.ClipboardSelectionClipboard, xclip.ClipboardSelectionPrimary:
+	default:
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(resp, "invalid selection %s, allowed selections are: clipboard, primary", clip)
+		return
+	}
 
-	line, err := io.ReadAll(req.Body)
+	selection, err := s.selections.Revert(clip, steps)
+	if err != nil {
+		resp.WriteHeader(http.StatusNotFound)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	s.persist()
+
+	slog.Info("Reverting clipboard", "selection", clip, "steps", steps, "target", selection.Target, logging.Content("content", selection.Content))
+	err = s.cli.Copy(
+		req.Context(),
+		bytes.NewReader(selection.Content),
+		xclip.CopyOptionSelection(clip),
+		xclip.CopyOptionWithTarget(selection.Target),
+	)
 	if err != nil {
-		log.Printf("Failed to read line: %v", err)
+		slog.Error("Failed to revert selection", "error", err)
+		resp.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(resp, "failed to revert selection: %v", err)
 		return
 	}
 
-	selection, ok := s.selections.FindMatch(line)
+	resp.Write(selection.Clean())
+}
+
+func (s *Service) HandlePrint(resp http.ResponseWriter, req *http.Request) {
+	unindentFlag := req.URL.Query().Get("unindent")
+	slog.Debug("Handle print", "unindent", unindentFlag)
+
+	id, ok := s.resolveID(resp, req)
 	if !ok {
-		log.Printf("No match found for line: %s", line)
 		return
 	}
 
+	var selection selections.Selection
+	var err error
+	if name := req.URL.Query().Get("register"); name != "" {
+		selection, ok = s.register(resp, name)
+		if !ok {
+			return
+		}
+	} else if id != "" {
+		selection, ok = s.selections.FindID(id)
+		if !ok {
+			resp.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(resp, "no selection found with id %s", id)
+			return
+		}
+	} else {
+		line, err := io.ReadAll(req.Body)
+		if err != nil {
+			slog.Warn("Failed to read line", "error", err)
+			return
+		}
+
+		selection, ok = s.selections.FindMatch(line)
+		if !ok {
+			// Not an error, the line is empty when the picker piping it was aborted
+			slog.Info("No match found for line", logging.Content("line", line))
+			return
+		}
+	}
+
 	if unindentFlag == "true" {
 		if selection.Target == xclip.ValidTargetImagePng {
 			convertOptions := convert.DefaultOptions
@@ -202,7 +538,7 @@ func (s *Service) HandlePrint(resp http.ResponseWriter, req *http.Request) {
 				convertOptions.FixedHeight, err = strconv.Atoi(height)
 			}
 
-			log.Printf("Preview with %d columns and %d lines", convertOptions.FixedWidth, convertOptions.FixedHeight)
+			slog.Debug("Preview image", "columns", convertOptions.FixedWidth, "lines", convertOptions.FixedHeight)
 
 			converter := convert.NewImageConverter()
 			imagefile, err := png.Decode(bytes.NewReader(selection.Content))
@@ -214,7 +550,7 @@ func (s *Service) HandlePrint(resp http.ResponseWriter, req *http.Request) {
 
 			_, err = resp.Write([]byte(ascii))
 			if err != nil {
-				log.Printf("Failed to write ascii: %v", err)
+				slog.Warn("Failed to write ascii", "error", err)
 			}
 		} else {
 			unindent(bytes.NewReader(selection.Content), resp)
@@ -222,11 +558,11 @@ func (s *Service) HandlePrint(resp http.ResponseWriter, req *http.Request) {
 	} else {
 		_, err = resp.Write(selection.Content)
 		if err != nil {
-			log.Printf("Failed to write selection: %v", err)
+			slog.Warn("Failed to write selection", "error", err)
 		}
 	}
 	if err != nil {
-		log.Printf("Failed to write selection: %v", err)
+		slog.Warn("Failed to write selection", "error", err)
 	}
 }
 
@@ -256,11 +592,11 @@ func unindent(in io.Reader, out io.Writer) {
 		removeMinIndentation := min(detectedIndentation, lineIndentation)
 		_, err := out.Write(line[removeMinIndentation:])
 		if err != nil {
-			log.Printf("Failed to write line: %v", err)
+			slog.Warn("Failed to write line", "error", err)
 		}
 		_, err = out.Write([]byte("\n"))
 		if err != nil {
-			log.Printf("Failed to write line: %v", err)
+			slog.Warn("Failed to write line", "error", err)
 		}
 	}
 }
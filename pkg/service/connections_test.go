@@ -2,10 +2,13 @@ package service
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,3 +101,74 @@ func TestUnindent(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleClear_bulk(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"https://example.com", "note", "https://example.org/docs"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	clear := func(query string) ClearResult {
+		rec := do(s.HandleClear, http.MethodPost, "/clear?all=true&"+query, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		result := ClearResult{}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+		return result
+	}
+
+	result := clear("match=^https&dry-run=true")
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Count)
+	assert.Len(t, s.selections.Entries(), 3)
+
+	result = clear("match=^https&larger-than=20B")
+	assert.False(t, result.DryRun)
+	require.Equal(t, 1, result.Count)
+	assert.Equal(t, "https://example.org/docs", result.Entries[0].Line)
+	assert.Len(t, s.selections.Entries(), 2)
+
+	rec := do(s.HandleClear, http.MethodPost, "/clear?all=true&older-than=soon", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// The all type keeps pinned selections unless the filter asks for them
+	pinned := s.selections.Entries()[1]
+	id := pinned.ID()
+	rec = do(s.HandlePin, http.MethodPost, "/pin?id="+id, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleClear, http.MethodPost, "/clear?id="+id, "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	result = clear("filter=category:pinned")
+	require.Equal(t, 1, result.Count)
+	assert.Equal(t, string(pinned.Clean()), result.Entries[0].Line)
+}
+
+func TestHandleClear_lines(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"ls -la\n", "pwd"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := do(s.HandleClear, http.MethodPost, "/clear", "ls -la\n\000pwd\000")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, s.selections.Entries())
+
+	rec = do(s.HandleClear, http.MethodPost, "/clear", "missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleCopy_unknown_line(t *testing.T) {
+	s := newTestService(t)
+
+	// An aborted picker pipes an empty line, it is not an error
+	for _, line := range []string{"", "missing"} {
+		rec := do(s.HandleCopy, http.MethodPost, "/copy", line)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec = do(s.HandlePrint, http.MethodPost, "/print", line)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"blueclip/pkg/archive"
+	"blueclip/pkg/listing"
+	"blueclip/pkg/search"
+	"blueclip/pkg/selections"
+	"time"
+)
+
+// SearchMatch is how well an entry matches the search query, empty without a query
+type SearchMatch struct {
+	// Score is higher for better matches
+	Score int `json:"score"`
+	// Offsets are the byte ranges [start, end) of the matches in Line
+	Offsets []search.Span `json:"offsets,omitempty"`
+	// LabelOffsets are set instead of Offsets when the label matched better than the content
+	LabelOffsets []search.Span `json:"label_offsets,omitempty"`
+}
+
+// SearchResult is the JSON representation of an entry found by a search
+type SearchResult struct {
+	listing.Entry
+	SearchMatch
+}
+
+// ArchiveEntry is the JSON representation of an archived selection
+type ArchiveEntry struct {
+	listing.Entry
+	SearchMatch
+	ArchivedAt time.Time `json:"archived_at"`
+	// Reason is why the selection was dropped from the history
+	Reason string `json:"reason"`
+}
+
+func NewArchiveEntry(index int, record archive.Record) ArchiveEntry {
+	return ArchiveEntry{
+		Entry:      listing.NewEntry(index, selections.Entry{Selection: record.Selection, Category: record.Category}),
+		ArchivedAt: record.ArchivedAt,
+		Reason:     record.Reason,
+	}
+}
+
+// TrashBatchEntry is the JSON representation of a batch in the trash
+type TrashBatchEntry struct {
+	ID        int             `json:"id"`
+	ClearedAt time.Time       `json:"cleared_at"`
+	Entries   []listing.Entry `json:"entries"`
+}
+
+func NewTrashBatchEntry(batch selections.TrashBatch) TrashBatchEntry {
+	entries := make([]listing.Entry, len(batch.Entries))
+	for i, entry := range batch.Entries {
+		entries[i] = listing.NewEntry(i, entry.Entry)
+	}
+	return TrashBatchEntry{
+		ID:        batch.ID,
+		ClearedAt: batch.ClearedAt,
+		Entries:   entries,
+	}
+}
+
+// ClearResult is the JSON representation of the selections removed by a clear with all,
+// or the ones that would be removed by a dry run
+type ClearResult struct {
+	Count   int             `json:"count"`
+	DryRun  bool            `json:"dry_run"`
+	Entries []listing.Entry `json:"entries"`
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"blueclip/pkg/logging"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type LogSettings struct {
+	Level   string `json:"level"`
+	Content bool   `json:"content"`
+}
+
+// HandleLog reports the log settings, a POST request changes them at runtime
+func (s *Service) HandleLog(resp http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		query := req.URL.Query()
+		if level := query.Get("level"); level != "" {
+			l, err := logging.ParseLevel(level)
+			if err != nil {
+				resp.WriteHeader(http.StatusBadRequest)
+				resp.Write([]byte(err.Error()))
+				return
+			}
+			logging.SetLevel(l)
+		}
+		if content := query.Get("content"); content != "" {
+			enabled, err := strconv.ParseBool(content)
+			if err != nil {
+				resp.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(resp, "invalid content value %q", content)
+				return
+			}
+			logging.SetContent(enabled)
+		}
+		slog.Info("Log settings changed", "level", logging.LevelName(logging.Level()), "content", logging.ContentEnabled())
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(resp).Encode(LogSettings{
+		Level:   logging.LevelName(logging.Level()),
+		Content: logging.ContentEnabled(),
+	})
+	if err != nil {
+		slog.Warn("Failed to write log settings", "error", err)
+	}
+}
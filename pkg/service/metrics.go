@@ -0,0 +1,107 @@
+package service
+
+import (
+	"blueclip/pkg/metrics"
+	"blueclip/pkg/selections"
+	"errors"
+	"os/exec"
+	"slices"
+	"time"
+)
+
+type serviceMetrics struct {
+	registry *metrics.Registry
+
+	captures *metrics.Counter
+	rejected *metrics.Counter
+	copies   *metrics.Counter
+
+	xclipInvocations *metrics.Counter
+	xclipErrors      *metrics.Counter
+	xclipDuration    *metrics.Histogram
+
+	saveDuration *metrics.Histogram
+	saveSize     *metrics.Gauge
+
+	categoryEntries *metrics.Gauge
+	categoryBytes   *metrics.Gauge
+	targetEntries   *metrics.Gauge
+	targetBytes     *metrics.Gauge
+}
+
+func newServiceMetrics(s *Service) *serviceMetrics {
+	r := metrics.NewRegistry()
+	m := &serviceMetrics{
+		registry: r,
+
+		captures: r.Counter("blueclip_captures_total", "Selections captured from the X selections.", "selection", "target"),
+		rejected: r.Counter("blueclip_captures_rejected_total", "Captured selections that were not added to the history.", "rule"),
+		copies:   r.Counter("blueclip_copies_total", "Selections copied back to the X selections.", "selection", "target"),
+
+		xclipInvocations: r.Counter("blueclip_xclip_invocations_total", "Number of xclip executions.", "mode"),
+		xclipErrors:      r.Counter("blueclip_xclip_errors_total", "Number of failed xclip executions.", "mode"),
+		xclipDuration:    r.Histogram("blueclip_xclip_duration_seconds", "Duration of xclip executions.", metrics.DefaultBuckets, "mode"),
+
+		saveDuration: r.Histogram("blueclip_save_duration_seconds", "Duration of history saves.", metrics.DefaultBuckets),
+		saveSize:     r.Gauge("blueclip_save_size_bytes", "Size of the history file after the last save."),
+
+		categoryEntries: r.Gauge("blueclip_history_entries", "Number of entries in the history by category.", "category"),
+		categoryBytes:   r.Gauge("blueclip_history_bytes", "Size of the entries in the history by category.", "category"),
+		targetEntries:   r.Gauge("blueclip_history_target_entries", "Number of entries in the history by target.", "target"),
+		targetBytes:     r.Gauge("blueclip_history_target_bytes", "Size of the entries in the history by target.", "target"),
+	}
+
+	r.OnWrite(func() {
+		stats := s.selections.Stats()
+		m.categoryEntries.Reset()
+		m.categoryBytes.Reset()
+		for category, usage := range stats.Categories {
+			m.categoryEntries.Set(float64(usage.Count), string(category))
+			m.categoryBytes.Set(float64(usage.Bytes), string(category))
+		}
+		m.targetEntries.Reset()
+		m.targetBytes.Reset()
+		for target, usage := range stats.Targets {
+			m.targetEntries.Set(float64(usage.Count), string(target))
+			m.targetBytes.Set(float64(usage.Bytes), string(target))
+		}
+	})
+
+	return m
+}
+
+// instrument wraps an xclip runner to record invocation metrics
+func (m *serviceMetrics) instrument(run func(*exec.Cmd) error) func(*exec.Cmd) error {
+	return func(cmd *exec.Cmd) error {
+		mode := xclipMode(cmd.Args)
+		start := time.Now()
+		err := run(cmd)
+		m.xclipInvocations.Inc(mode)
+		m.xclipDuration.Observe(time.Since(start).Seconds(), mode)
+		if err != nil {
+			m.xclipErrors.Inc(mode)
+		}
+		return err
+	}
+}
+
+func xclipMode(args []string) string {
+	switch {
+	case slices.Contains(args, "-i"):
+		return "copy"
+	case slices.Contains(args, "TARGETS"):
+		return "targets"
+	default:
+		return "paste"
+	}
+}
+
+// rejectionRule names the reason a selection was not added to the history
+func rejectionRule(err error) string {
+	switch {
+	case errors.Is(err, selections.ErrAlreadyLast):
+		return "already_last"
+	default:
+		return "unknown"
+	}
+}
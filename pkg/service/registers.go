@@ -0,0 +1,137 @@
+package service
+
+import (
+	"blueclip/pkg/listing"
+	"blueclip/pkg/logging"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RegisterEntry is the JSON representation of a register and the selection it holds
+type RegisterEntry struct {
+	Name string `json:"name"`
+	listing.Entry
+}
+
+func NewRegisterEntry(register selections.Register) RegisterEntry {
+	return RegisterEntry{
+		Name:  register.Name,
+		Entry: listing.NewEntry(0, selections.Entry{Selection: register.Selection}),
+	}
+}
+
+// HandleRegisters returns the registers holding a selection as a RegisterEntry array
+func (s *Service) HandleRegisters(resp http.ResponseWriter, req *http.Request) {
+	registers := []RegisterEntry{}
+	for _, register := range s.selections.ListRegisters() {
+		registers = append(registers, NewRegisterEntry(register))
+	}
+	writeJSON(resp, registers)
+}
+
+// HandleRegisterSet stores the request body in the register given by the name parameter.
+// Without a body it stores the selection given by id or a selector, the last selection by default.
+func (s *Service) HandleRegisterSet(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	name := query.Get("name")
+	if err := selections.ValidRegister(name); err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(req.Body, MaxAddSize+1))
+	if err != nil {
+		slog.Warn("Failed to read content", "error", err)
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("failed to read content"))
+		return
+	}
+	if len(content) > MaxAddSize {
+		resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		fmt.Fprintf(resp, "content is larger than %d bytes", MaxAddSize)
+		return
+	}
+
+	var selection selections.Selection
+	if len(content) > 0 {
+		target := xclip.ValidTarget(query.Get("target"))
+		if target == xclip.ValidTargetUnknown {
+			target, err = DetectTarget(content)
+			if err != nil {
+				resp.WriteHeader(http.StatusBadRequest)
+				resp.Write([]byte(err.Error()))
+				return
+			}
+		} else if !validAddTarget(target) {
+			resp.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(resp, "invalid target %s, allowed targets are: %v", target, AddTargets)
+			return
+		}
+		selection = selections.Selection{
+			Selection:  xclip.NewSelection(content, target, ""),
+			CapturedAt: time.Now(),
+		}
+		err = s.selections.SetRegister(name, selection)
+	} else {
+		id, ok := s.resolveID(resp, req)
+		if !ok {
+			return
+		}
+		selection, err = s.selections.SetRegisterID(name, id)
+	}
+	switch {
+	case errors.Is(err, selections.ErrNoMatch):
+		resp.WriteHeader(http.StatusNotFound)
+		resp.Write([]byte(err.Error()))
+		return
+	case err != nil:
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	s.persist()
+	slog.Info("Stored selection in register", "register", name, "target", selection.Target, logging.Content("content", selection.Content))
+
+	writeJSON(resp, NewRegisterEntry(selections.Register{Name: name, Selection: selection}))
+}
+
+// HandleRegisterClear empties the register given by the name parameter
+func (s *Service) HandleRegisterClear(resp http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	if err := selections.ValidRegister(name); err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	if !s.selections.ClearRegister(name) {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "register %s is empty", name)
+		return
+	}
+	s.persist()
+	slog.Info("Cleared register", "register", name)
+	resp.WriteHeader(http.StatusOK)
+}
+
+// register returns the selection in the register given by the name, on failure the error is written
+func (s *Service) register(resp http.ResponseWriter, name string) (selections.Selection, bool) {
+	if err := selections.ValidRegister(name); err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return selections.Selection{}, false
+	}
+	selection, ok := s.selections.GetRegister(name)
+	if !ok {
+		resp.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(resp, "register %s is empty", name)
+		return selections.Selection{}, false
+	}
+	return selection, true
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"blueclip/pkg/config"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRegisters(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"first", "second"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := do(s.HandleRegisterSet, http.MethodPost, "/register/set?name=a", "from stdin")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	register := RegisterEntry{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&register))
+	assert.Equal(t, "a", register.Name)
+	assert.Equal(t, "from stdin", register.Line)
+
+	rec = do(s.HandleRegisterSet, http.MethodPost, "/register/set?name=b&match=first", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleRegisterSet, http.MethodPost, "/register/set?name=1", "content")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(s.HandlePrint, http.MethodPost, "/print?register=b", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "first", rec.Body.String())
+	rec = do(s.HandlePrint, http.MethodPost, "/print?register=c", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Registers are stored with the history
+	reloaded, err := NewService(s.db, &config.Config{})
+	require.NoError(t, err)
+	require.NoError(t, s.db.Load(reloaded.selections))
+	registers := []RegisterEntry{}
+	rec = do(reloaded.HandleRegisters, http.MethodGet, "/registers", "")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&registers))
+	require.Len(t, registers, 2)
+	assert.Equal(t, "b", registers[1].Name)
+
+	rec = do(s.HandleRegisterClear, http.MethodPost, "/register/clear?name=a", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleRegisterClear, http.MethodPost, "/register/clear?name=a", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(s.HandleRegisterClear, http.MethodPost, "/register/clear?name=1", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleRegisters_while_saving(t *testing.T) {
+	s := newTestService(t)
+	rec := do(s.HandleAdd, http.MethodPost, "/add", "content")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Registers change under the set lock while a capture saves under the service lock
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			do(s.HandleRegisterSet, http.MethodPost, "/register/set?name=a", "")
+			do(s.HandleRegisterClear, http.MethodPost, "/register/clear?name=a", "")
+		}
+	}()
+	for range 100 {
+		s.persist()
+	}
+	<-done
+	require.NoError(t, s.saveError)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"blueclip/pkg/bytesize"
+	"blueclip/pkg/duration"
+	"blueclip/pkg/filter"
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// selectorFromQuery reads the selector parameters, ok is false if none is set
+func selectorFromQuery(query url.Values) (selector selections.Selector, ok bool, err error) {
+	if value := query.Get("index"); value != "" {
+		index, err := strconv.Atoi(value)
+		if err != nil {
+			return selector, false, fmt.Errorf("invalid index %s", value)
+		}
+		selector.Index = &index
+		ok = true
+	}
+	if value := query.Get("category"); value != "" {
+		switch category := selections.SelectionRetentionType(value); category {
+		case selections.SelectionRetentionTypeAll,
+			selections.SelectionRetentionTypeEphemeral,
+			selections.SelectionRetentionTypeImportant,
+			selections.SelectionRetentionTypePinned:
+			selector.Category = category
+		default:
+			return selector, false, fmt.Errorf("invalid category %s, allowed categories are: all, ephemeral, important, pinned", value)
+		}
+		ok = true
+	}
+	if value := query.Get("target"); value != "" {
+		selector.Target = xclip.ValidTarget(value)
+		ok = true
+	}
+	if value := query.Get("match"); value != "" {
+		match, err := regexp.Compile(value)
+		if err != nil {
+			return selector, false, fmt.Errorf("invalid match: %v", err)
+		}
+		selector.Match = match
+		ok = true
+	}
+	if value := query.Get("older-than"); value != "" {
+		olderThan, err := duration.Parse(value)
+		if err != nil {
+			return selector, false, fmt.Errorf("invalid older-than: %v", err)
+		}
+		selector.OlderThan = olderThan
+		ok = true
+	}
+	if value := query.Get("larger-than"); value != "" {
+		largerThan, err := bytesize.Parse(value)
+		if err != nil {
+			return selector, false, fmt.Errorf("invalid larger-than: %v", err)
+		}
+		selector.LargerThan = largerThan
+		ok = true
+	}
+	if value := query.Get("filter"); value != "" {
+		f, err := filter.Parse(value)
+		if err != nil {
+			return selector, false, err
+		}
+		selector.Filter = func(entry selections.Entry) bool {
+			return f.Matches(entry, time.Now())
+		}
+		// Like --category, a category term narrows the type of a clear
+		if category := f.Category(); category != "" && (selector.Category == "" || selector.Category == selections.SelectionRetentionTypeAll) {
+			selector.Category = category
+		}
+		ok = true
+	}
+	// The order alone does not select anything, it only ranks the entries for the index
+	order, err := selections.ParseOrder(query.Get("order"))
+	if err != nil {
+		return selector, false, err
+	}
+	selector.Order = order
+	return selector, ok, nil
+}
+
+// resolveID returns the id given in the request or the id of the entry picked by the selector.
+// The id is empty if the request has neither. On failure the error is written and ok is false.
+func (s *Service) resolveID(resp http.ResponseWriter, req *http.Request) (id string, ok bool) {
+	query := req.URL.Query()
+	if id := query.Get("id"); id != "" {
+		return id, true
+	}
+
+	selector, found, err := selectorFromQuery(query)
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte(err.Error()))
+		return "", false
+	}
+	if !found {
+		return "", true
+	}
+
+	entry, err := s.selections.Select(selector)
+	switch {
+	case errors.Is(err, selections.ErrAmbiguous):
+		resp.WriteHeader(http.StatusConflict)
+		resp.Write([]byte(err.Error()))
+		return "", false
+	case err != nil:
+		resp.WriteHeader(http.StatusNotFound)
+		resp.Write([]byte(err.Error()))
+		return "", false
+	}
+	return entry.ID(), true
+}
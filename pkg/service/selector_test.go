@@ -0,0 +1,46 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlePrint_with_selector(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"one", "two", "three"} {
+		s.selections.Add(selections.Selection{
+			Selection: xclip.NewSelection([]byte(content), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard),
+		})
+	}
+
+	tests := []struct {
+		query string
+		code  int
+		body  string
+	}{
+		{query: "index=1", code: http.StatusOK, body: "two"},
+		{query: "match=^t&index=0", code: http.StatusOK, body: "three"},
+		{query: "match=o$", code: http.StatusOK, body: "two"},
+		{query: "match=^t", code: http.StatusConflict},
+		{query: "index=3", code: http.StatusNotFound},
+		{query: "category=last", code: http.StatusBadRequest},
+		{query: "match=(", code: http.StatusBadRequest},
+		{query: "order=size&index=0", code: http.StatusOK, body: "three"},
+		{query: "order=random&index=0", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := do(s.HandlePrint, http.MethodPost, "/print?"+tt.query, "")
+			require.Equal(t, tt.code, rec.Code, rec.Body.String())
+			if tt.body != "" {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+}
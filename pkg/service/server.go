@@ -1,38 +1,121 @@
 package service
 
 import (
+	"blueclip/pkg/archive"
+	"blueclip/pkg/config"
 	"blueclip/pkg/db"
+	"blueclip/pkg/hooks"
+	"blueclip/pkg/logging"
 	"blueclip/pkg/selections"
 	"blueclip/pkg/xclip"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
 
 type Service struct {
-	db *db.FileDB
+	db      *db.FileDB
+	hooks   *hooks.Runner
+	metrics *serviceMetrics
+	// cli runs xclip recording the invocation metrics
+	cli *xclip.XClip
+
+	// metricsAddr is an optional TCP address where metrics are exposed
+	metricsAddr string
+	// keepCategory is the default copy mode when the request doesn't set it
+	keepCategory bool
+	// policy moves selections between categories on captures, copies and every policyInterval
+	policy         selections.Policy
+	policyInterval time.Duration
+	// retention limits the history, it replaces the limits stored with the history
+	retention selections.Options
+	// archive keeps the selections dropped from the history, nil if disabled
+	archive *archive.Archive
+	// pending are the dropped selections waiting to be archived
+	pendingLock sync.Mutex
+	pending     []archive.Record
+
+	startedAt time.Time
+	watchers  []*xclip.WatchStatus
 
 	lock       sync.Mutex
 	selections *selections.Set
+	lastSave   time.Time
+	saveError  error
 }
 
-func NewService(db *db.FileDB) *Service {
-	return &Service{
-		db:         db,
-		selections: selections.NewSelections(),
+func NewService(db *db.FileDB, cfg *config.Config) (*Service, error) {
+	runner, err := hooks.NewRunner(cfg.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hooks: %v", err)
+	}
+
+	s := &Service{
+		db:             db,
+		hooks:          runner,
+		metricsAddr:    cfg.Metrics.Listen,
+		keepCategory:   cfg.Copy.KeepCategory,
+		policy:         cfg.Policy.Policy(),
+		policyInterval: cfg.Policy.EvaluationInterval(),
+		retention:      cfg.SelectionOptions(),
+		selections:     selections.NewSelections(),
 	}
+	s.metrics = newServiceMetrics(s)
+	s.cli = &xclip.XClip{RunFn: s.metrics.instrument(xclip.Cli.RunFn)}
+
+	if !cfg.Archive.Disabled {
+		path := cfg.Archive.Path
+		if path == "" {
+			path = strings.TrimSuffix(db.Path, filepath.Ext(db.Path)) + ".archive"
+		}
+		s.archive, err = archive.New(path, cfg.Archive.Options())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create archive: %v", err)
+		}
+		s.selections.OnEvict(s.archiveEvicted)
+	}
+	return s, nil
 }
 
 func (s *Service) runListener(ctx context.Context) error {
-	log.Println("Starting service...")
+	slog.Info("Starting service")
 	mux := http.NewServeMux()
 	mux.HandleFunc("/copy", s.HandleCopy)
 	mux.HandleFunc("/print", s.HandlePrint)
 	mux.HandleFunc("/list", s.HandleList)
 	mux.HandleFunc("/clear", s.HandleClear)
+	mux.HandleFunc("/promote", s.HandlePromote)
+	mux.HandleFunc("/demote", s.HandleDemote)
+	mux.HandleFunc("/pin", s.HandlePin)
+	mux.HandleFunc("/unpin", s.HandleUnpin)
+	mux.HandleFunc("/add", s.HandleAdd)
+	mux.HandleFunc("/search", s.HandleSearch)
+	mux.HandleFunc("/archive/search", s.HandleArchiveSearch)
+	mux.HandleFunc("/archive/restore", s.HandleArchiveRestore)
+	mux.HandleFunc("/undo", s.HandleUndo)
+	mux.HandleFunc("/revert", s.HandleRevert)
+	mux.HandleFunc("/label", s.HandleLabel)
+	mux.HandleFunc("/registers", s.HandleRegisters)
+	mux.HandleFunc("/register/set", s.HandleRegisterSet)
+	mux.HandleFunc("/register/clear", s.HandleRegisterClear)
+	mux.HandleFunc("/tags", s.HandleTags)
+	mux.HandleFunc("/tag/add", s.HandleTagAdd)
+	mux.HandleFunc("/tag/rm", s.HandleTagRemove)
+	mux.HandleFunc("/collections", s.HandleCollections)
+	mux.HandleFunc("/collection/add", s.HandleCollectionAdd)
+	mux.HandleFunc("/collection/rm", s.HandleCollectionRemove)
+	mux.HandleFunc("/trash", s.HandleTrash)
+	mux.HandleFunc("/trash/restore", s.HandleTrashRestore)
+	mux.HandleFunc("/trash/empty", s.HandleTrashEmpty)
+	mux.HandleFunc("/status", s.HandleStatus)
+	mux.HandleFunc("/log", s.HandleLog)
+	mux.Handle("/metrics", s.metrics.registry.Handler())
 
 	server, err := NewServer(mux)
 	if err != nil {
@@ -40,28 +123,59 @@ func (s *Service) runListener(ctx context.Context) error {
 	}
 
 	go func() {
-		log.Printf("Listening on %s", server.Addr)
+		slog.Info("Listening", "socket", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("Server error: %v", err)
+			slog.Error("Server error", "error", err)
 			// how to handle this?
 			panic(err)
 		}
 	}()
 
+	if s.metricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", s.metrics.registry.Handler())
+		metricsServer := &http.Server{
+			Addr:         s.metricsAddr,
+			Handler:      metricsMux,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+		}
+		go func() {
+			slog.Info("Exposing metrics", "url", "http://"+s.metricsAddr+"/metrics")
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Metrics server error", "error", err)
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			metricsServer.Close()
+		}()
+	}
+
 	return nil
 }
 
 func (s *Service) Run(ctx context.Context) error {
-	log.Printf("Loading selections from %s", s.db.Path)
+	s.startedAt = time.Now()
+
+	slog.Info("Loading selections", "path", s.db.Path)
 	err := s.db.Load(s.selections)
 	if err != nil {
-		log.Fatalf("Failed to load selections at %s: %v", s.db.Path, err)
-	} else {
-		log.Printf("Loaded %d ephemeral and %d important selections", len(s.selections.Ephemeral), len(s.selections.Important))
+		return fmt.Errorf("failed to load selections at %s: %v", s.db.Path, err)
 	}
+	slog.Info("Loaded selections", "ephemeral", len(s.selections.Ephemeral), "important", len(s.selections.Important))
+	s.selections.SetOptions(s.retention)
+	s.archivePending()
+	s.compactArchive()
+	s.purgeTrash()
 
-	clipboard := xclip.Cli.Watch(
+	clipboardStatus := &xclip.WatchStatus{}
+	primaryStatus := &xclip.WatchStatus{}
+	s.watchers = []*xclip.WatchStatus{clipboardStatus, primaryStatus}
+
+	clipboard := s.cli.Watch(
 		ctx,
+		xclip.WatchOptionWithStatus(clipboardStatus),
 		xclip.WatchOptionWithMonitorTargets([]xclip.ValidTarget{xclip.ValidTargetTIMESTAMP}),
 		xclip.WatchOptionWithTargetPriority([]xclip.ValidTarget{
 			xclip.ValidTargetxSpecialGnomeCopiedFiles,
@@ -72,8 +186,9 @@ func (s *Service) Run(ctx context.Context) error {
 		xclip.WatchOptionWithFrequency(1000*time.Millisecond),
 	)
 
-	primary := xclip.Cli.Watch(
+	primary := s.cli.Watch(
 		ctx,
+		xclip.WatchOptionWithStatus(primaryStatus),
 		xclip.WatchOptionWithMonitorTargets([]xclip.ValidTarget{xclip.ValidTargetTIMESTAMP}),
 		xclip.WatchOptionWithTargetPriority([]xclip.ValidTarget{
 			xclip.ValidTargetxSpecialGnomeCopiedFiles,
@@ -90,12 +205,20 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 
 	// Handle clipboard changes
-	log.Printf("Watching clipboard for changes...")
+	slog.Info("Watching clipboard for changes")
+
+	maintenance := time.NewTicker(s.policyInterval)
+	defer maintenance.Stop()
+	s.evaluatePolicy(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-maintenance.C:
+			s.evaluatePolicy(ctx)
+			s.compactArchive()
+			s.purgeTrash()
 		case data := <-clipboard:
 			s.handleClipboardChange(ctx, data)
 		case data := <-primary:
@@ -104,15 +227,78 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 }
 func (s *Service) handleClipboardChange(ctx context.Context, data xclip.Selection) {
+	defer s.archivePending()
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.selections.Add(selections.Selection{
-		Selection: data,
+	sel := selections.Selection{
+		Selection:  data,
+		CapturedAt: time.Now(),
+	}
+	category, err := s.selections.Add(sel)
+	if err != nil {
+		slog.Debug("Selection not added", "reason", err)
+		s.metrics.rejected.Inc(rejectionRule(err))
+		return
+	}
+	s.metrics.captures.Inc(string(data.Clipboard), string(data.Target))
+
+	s.applyPolicy(ctx)
+	s.save()
+
+	s.hooks.Fire(ctx, hooks.Payload{
+		Event:      hooks.EventCapture,
+		Entry:      selections.Entry{Selection: sel, Category: category},
+		Clipboards: []xclip.ClipboardSelection{data.Clipboard},
 	})
+}
 
-	err := s.db.Save(s.selections)
-	if err != nil {
-		log.Printf("Failed to save selections: %s", err)
+// evaluatePolicy applies the policy outside of captures and copies
+func (s *Service) evaluatePolicy(ctx context.Context) {
+	defer s.archivePending()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.applyPolicy(ctx) {
+		s.save()
+	}
+}
+
+// applyPolicy moves selections according to the policy and reports if anything changed,
+// the caller must hold the service lock and save
+func (s *Service) applyPolicy(ctx context.Context) bool {
+	transitions := s.selections.ApplyPolicy(s.policy, time.Now())
+	for _, t := range transitions {
+		if t.To == "" {
+			slog.Info("Policy expired selection", "category", t.Category, "reason", t.Reason, logging.Content("selection", t.Content))
+			s.archiveEvicted([]selections.Eviction{{Entry: t.Entry, Reason: "expired, " + t.Reason}})
+			s.fireClear(ctx, []selections.Entry{t.Entry})
+			continue
+		}
+		slog.Info("Policy moved selection", "from", t.Category, "to", t.To, "reason", t.Reason, logging.Content("selection", t.Content))
+	}
+	return len(transitions) > 0
+}
+
+// persist saves the selections after a change made outside of a capture
+func (s *Service) persist() {
+	defer s.archivePending()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.save()
+}
+
+// save persists the selections, the caller must hold the service lock
+func (s *Service) save() {
+	start := time.Now()
+	s.saveError = s.db.Save(s.selections)
+	if s.saveError != nil {
+		slog.Error("Failed to save selections", "error", s.saveError)
+		return
+	}
+	s.lastSave = time.Now()
+	s.metrics.saveDuration.Observe(s.lastSave.Sub(start).Seconds())
+
+	if info, err := os.Stat(s.db.Path); err == nil {
+		s.metrics.saveSize.Set(float64(info.Size()))
 	}
 }
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"blueclip/pkg/config"
+	"blueclip/pkg/db"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestService returns a service with the default configuration storing its history in a temporary directory
+func newTestService(t *testing.T) *Service {
+	return newTestServiceWithConfig(t, &config.Config{})
+}
+
+// newTestServiceWithConfig is like newTestService but applies the limits of the configuration as Run does
+func newTestServiceWithConfig(t *testing.T, cfg *config.Config) *Service {
+	fileDB, err := db.NewFileDB(filepath.Join(t.TempDir(), "history.bin"))
+	require.NoError(t, err)
+	s, err := NewService(fileDB, cfg)
+	require.NoError(t, err)
+	s.selections.SetOptions(s.retention)
+	return s
+}
+
+// do sends a request with the body to the handler and returns the response
+func do(handler http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
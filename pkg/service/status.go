@@ -0,0 +1,81 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Version is the server version, it is set at build time with
+// -ldflags "-X blueclip/pkg/service.Version=..."
+var Version = "dev"
+
+type Status struct {
+	Version   string             `json:"version"`
+	StartedAt time.Time          `json:"started_at"`
+	Uptime    Duration           `json:"uptime"`
+	Watchers  []xclip.WatchState `json:"watchers"`
+	Stats     selections.Stats   `json:"stats"`
+	Storage   StorageStatus      `json:"storage"`
+}
+
+type StorageStatus struct {
+	Path      string    `json:"path"`
+	LastSave  time.Time `json:"last_save"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Duration is a time.Duration encoded as a human readable string
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (s *Service) Status() Status {
+	status := Status{
+		Version:   Version,
+		StartedAt: s.startedAt,
+		Uptime:    Duration(time.Since(s.startedAt).Round(time.Second)),
+		Stats:     s.selections.Stats(),
+		Storage: StorageStatus{
+			Path: s.db.Path,
+		},
+	}
+	for _, w := range s.watchers {
+		status.Watchers = append(status.Watchers, w.State())
+	}
+
+	s.lock.Lock()
+	status.Storage.LastSave = s.lastSave
+	if s.saveError != nil {
+		status.Storage.LastError = s.saveError.Error()
+	}
+	s.lock.Unlock()
+
+	return status
+}
+
+func (s *Service) HandleStatus(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(resp).Encode(s.Status())
+	if err != nil {
+		slog.Warn("Failed to write status", "error", err)
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"blueclip/pkg/xclip"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleStatus(t *testing.T) {
+	s := newTestService(t)
+
+	s.selections.Add(selections.Selection{
+		Selection: xclip.NewSelection([]byte("hello"), xclip.ValidTargetUTF8_STRING, xclip.ClipboardSelectionClipboard),
+	})
+
+	rec := do(s.HandleStatus, http.MethodGet, "/status", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	status := Status{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+	assert.Equal(t, Version, status.Version)
+	assert.Equal(t, s.db.Path, status.Storage.Path)
+	assert.Equal(t, selections.Usage{Count: 1, Bytes: 5}, status.Stats.Categories[selections.SelectionRetentionTypeEphemeral])
+}
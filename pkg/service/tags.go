@@ -0,0 +1,65 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"net/http"
+	"strings"
+)
+
+// HandleTags returns how many selections have each tag as a JSON object
+func (s *Service) HandleTags(resp http.ResponseWriter, req *http.Request) {
+	writeJSON(resp, s.selections.Tags())
+}
+
+// HandleTagAdd adds the tag parameters to the selection given by id or a selector
+func (s *Service) HandleTagAdd(resp http.ResponseWriter, req *http.Request) {
+	s.handleNames(resp, req, "tag", "Tagged", s.selections.Tag)
+}
+
+// HandleTagRemove removes the tag parameters from the selection given by id or a selector
+func (s *Service) HandleTagRemove(resp http.ResponseWriter, req *http.Request) {
+	s.handleNames(resp, req, "tag", "Untagged", s.selections.Untag)
+}
+
+// HandleLabel replaces the label of the selection given by id or a selector, an empty label removes it
+func (s *Service) HandleLabel(resp http.ResponseWriter, req *http.Request) {
+	label := strings.TrimSpace(req.URL.Query().Get("label"))
+	s.handleMove(resp, req, "Labeled", func(id string) (selections.Selection, error) {
+		return s.selections.SetLabel(id, label)
+	})
+}
+
+// HandleCollections returns how many selections are in each collection as a JSON object
+func (s *Service) HandleCollections(resp http.ResponseWriter, req *http.Request) {
+	writeJSON(resp, s.selections.Collections())
+}
+
+// HandleCollectionAdd adds the selection given by id or a selector to the collections in the name parameters
+func (s *Service) HandleCollectionAdd(resp http.ResponseWriter, req *http.Request) {
+	s.handleNames(resp, req, "name", "Collected", s.selections.Collect)
+}
+
+// HandleCollectionRemove removes the selection given by id or a selector from the collections in the name parameters
+func (s *Service) HandleCollectionRemove(resp http.ResponseWriter, req *http.Request) {
+	s.handleNames(resp, req, "name", "Uncollected", s.selections.Uncollect)
+}
+
+// handleNames validates the names in the parameter and applies them to the selection like handleMove
+func (s *Service) handleNames(resp http.ResponseWriter, req *http.Request, param string, action string, apply func(id string, names ...string) (selections.Selection, error)) {
+	names := req.URL.Query()[param]
+	if len(names) == 0 {
+		resp.WriteHeader(http.StatusBadRequest)
+		resp.Write([]byte("missing " + param))
+		return
+	}
+	for _, name := range names {
+		if err := selections.ValidName(name); err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			resp.Write([]byte(err.Error()))
+			return
+		}
+	}
+	s.handleMove(resp, req, action, func(id string) (selections.Selection, error) {
+		return apply(id, names...)
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"blueclip/pkg/listing"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTags(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"first", "second"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := do(s.HandleTagAdd, http.MethodPost, "/tag/add?match=first&tag=work&tag=todo", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleTagRemove, http.MethodPost, "/tag/rm?match=first&tag=todo", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleCollectionAdd, http.MethodPost, "/collection/add?index=0&name=snippets", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(s.HandleTagAdd, http.MethodPost, "/tag/add?index=0&tag=two+words", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(s.HandleTagAdd, http.MethodPost, "/tag/add?index=0", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(s.HandleCollectionAdd, http.MethodPost, "/collection/add?id=unknown&name=snippets", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	counts := map[string]int{}
+	rec = do(s.HandleTags, http.MethodGet, "/tags", "")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&counts))
+	assert.Equal(t, map[string]int{"work": 1}, counts)
+	counts = map[string]int{}
+	rec = do(s.HandleCollections, http.MethodGet, "/collections", "")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&counts))
+	assert.Equal(t, map[string]int{"snippets": 1}, counts)
+
+	listed := []listing.Entry{}
+	rec = do(s.HandleList, http.MethodGet, "/list?format=json&filter=tag:work", "")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "first", listed[0].Line)
+	assert.Equal(t, []string{"work"}, listed[0].Tags)
+	assert.Equal(t, 1, listed[0].Index)
+}
+
+func TestHandleLabel(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"DELETE FROM sessions WHERE expired", "select 1"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := do(s.HandleLabel, http.MethodPost, "/label?match=^DELETE&label=prod+db+cleanup", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(s.HandleSearch, http.MethodGet, "/search?mode=fuzzy&query=proddb", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	found := []SearchResult{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&found))
+	require.Len(t, found, 1)
+	assert.Equal(t, "prod db cleanup", found[0].Label)
+	assert.Empty(t, found[0].Offsets)
+	assert.NotEmpty(t, found[0].LabelOffsets)
+
+	rec = do(s.HandleLabel, http.MethodPost, "/label?index=1&label=", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, s.selections.Entries()[1].Label)
+}
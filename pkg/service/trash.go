@@ -0,0 +1,74 @@
+package service
+
+import (
+	"blueclip/pkg/selections"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HandleTrash returns the batches in the trash as a TrashBatchEntry array, the most recent first
+func (s *Service) HandleTrash(resp http.ResponseWriter, req *http.Request) {
+	batches := s.selections.TrashBatches()
+	list := make([]TrashBatchEntry, len(batches))
+	for i, batch := range batches {
+		list[i] = NewTrashBatchEntry(batch)
+	}
+	writeJSON(resp, list)
+}
+
+// HandleUndo restores the selections removed by the most recent clear
+func (s *Service) HandleUndo(resp http.ResponseWriter, req *http.Request) {
+	s.handleRestoreTrash(resp, s.selections.Undo)
+}
+
+// HandleTrashRestore restores the selections of the batch given by the batch parameter
+func (s *Service) HandleTrashRestore(resp http.ResponseWriter, req *http.Request) {
+	value := req.URL.Query().Get("batch")
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(resp, "invalid batch %q", value)
+		return
+	}
+	s.handleRestoreTrash(resp, func() (selections.TrashBatch, error) {
+		return s.selections.RestoreTrash(id)
+	})
+}
+
+func (s *Service) handleRestoreTrash(resp http.ResponseWriter, restore func() (selections.TrashBatch, error)) {
+	batch, err := restore()
+	switch {
+	case errors.Is(err, selections.ErrTrashEmpty), errors.Is(err, selections.ErrNoMatch):
+		resp.WriteHeader(http.StatusNotFound)
+		resp.Write([]byte(err.Error()))
+		return
+	case err != nil:
+		resp.WriteHeader(http.StatusInternalServerError)
+		resp.Write([]byte(err.Error()))
+		return
+	}
+	s.persist()
+	writeJSON(resp, NewTrashBatchEntry(batch))
+}
+
+// HandleTrashEmpty removes every selection in the trash for good
+func (s *Service) HandleTrashEmpty(resp http.ResponseWriter, req *http.Request) {
+	removed := s.selections.EmptyTrash()
+	slog.Info("Emptied trash", "batches", len(removed))
+	s.persist()
+	resp.WriteHeader(http.StatusOK)
+}
+
+// purgeTrash removes the batches older than the trash retention
+func (s *Service) purgeTrash() {
+	removed := s.selections.PurgeTrash(time.Now())
+	if len(removed) == 0 {
+		return
+	}
+	slog.Info("Purged trash", "batches", len(removed))
+	s.persist()
+}
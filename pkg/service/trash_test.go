@@ -0,0 +1,51 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTrash(t *testing.T) {
+	s := newTestService(t)
+
+	for _, content := range []string{"first", "second", "third"} {
+		rec := do(s.HandleAdd, http.MethodPost, "/add", content)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := do(s.HandleClear, http.MethodPost, "/clear", "first\000second")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleClear, http.MethodPost, "/clear?permanent=true", "third")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, s.selections.Entries())
+
+	batches := []TrashBatchEntry{}
+	rec = do(s.HandleTrash, http.MethodGet, "/trash", "")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&batches))
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0].Entries, 2)
+
+	rec = do(s.HandleUndo, http.MethodPost, "/undo", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, s.selections.Entries(), 2)
+
+	rec = do(s.HandleUndo, http.MethodPost, "/undo", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(s.HandleTrashRestore, http.MethodPost, "/trash/restore?batch=1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(s.HandleClear, http.MethodPost, "/clear?all=true", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(s.HandleTrashRestore, http.MethodPost, "/trash/restore?batch=1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, s.selections.Entries(), 2)
+
+	s.selections.ClearAll("all")
+	rec = do(s.HandleTrashEmpty, http.MethodPost, "/trash/empty", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, s.selections.TrashBatches())
+}
@@ -29,19 +29,19 @@ func (m *MockExecer) AddCallN(args string, output []byte, n int) {
 	}
 }
 
+// Run writes the next output added for the args. The last output is repeated,
+// so a watcher can keep polling until it is stopped.
 func (m *MockExecer) Run(cmd *exec.Cmd) error {
 	args := strings.Join(cmd.Args, " ")
 	v, ok := m.calls[args]
 	if !ok {
-		m.t.Fatalf("Run() called with unexpected args: %s", args)
+		// Run may be called from a watcher goroutine, where Fatalf must not be used
+		m.t.Errorf("Run() called with unexpected args: %s", args)
 		return fmt.Errorf("Run() called with unexpected args: %s", args)
 	}
 	cmd.Stdout.Write(v[0])
-	v = v[1:]
-	if len(v) > 0 {
-		m.calls[args] = v
-	} else {
-		delete(m.calls, args)
+	if len(v) > 1 {
+		m.calls[args] = v[1:]
 	}
 	return nil
 }
@@ -3,8 +3,10 @@ package xclip
 type Selection struct {
 	Content []byte
 	Target  ValidTarget
+	// Clipboard is the X selection the content was read from, if known
+	Clipboard ClipboardSelection
 }
 
-func NewSelection(content []byte, t ValidTarget) Selection {
-	return Selection{Content: content, Target: t}
+func NewSelection(content []byte, t ValidTarget, clip ClipboardSelection) Selection {
+	return Selection{Content: content, Target: t, Clipboard: clip}
 }
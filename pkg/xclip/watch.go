@@ -4,13 +4,53 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"os/exec"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
+// WatchStatus records the health of a running watch, it is safe for concurrent use
+type WatchStatus struct {
+	lock  sync.Mutex
+	state WatchState
+}
+
+// WatchState is a snapshot of a WatchStatus
+type WatchState struct {
+	Selection   ClipboardSelection `json:"selection"`
+	Running     bool               `json:"running"`
+	LastPoll    time.Time          `json:"last_poll"`
+	LastChange  time.Time          `json:"last_change"`
+	LastError   string             `json:"last_error,omitempty"`
+	LastErrorAt time.Time          `json:"last_error_at"`
+}
+
+func (w *WatchStatus) State() WatchState {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.state
+}
+
+func (w *WatchStatus) update(fn func(*WatchState)) {
+	if w == nil {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	fn(&w.state)
+}
+
+func (w *WatchStatus) error(err error) {
+	w.update(func(s *WatchState) {
+		s.LastError = strings.TrimSpace(err.Error())
+		s.LastErrorAt = time.Now()
+	})
+}
+
 type WatchOptions struct {
 	Silent bool
 	// monitorTargets is the list of targets to for changes.
@@ -22,6 +62,7 @@ type WatchOptions struct {
 
 	clip      ClipboardSelection
 	frequency time.Duration
+	status    *WatchStatus
 }
 
 type WatchOption func(*WatchOptions) error
@@ -50,6 +91,14 @@ func WatchOptionWithFrequency(frequency time.Duration) WatchOption {
 	}
 }
 
+// WatchOptionWithStatus reports the watch health to the given status
+func WatchOptionWithStatus(status *WatchStatus) WatchOption {
+	return func(o *WatchOptions) error {
+		o.status = status
+		return nil
+	}
+}
+
 func WatchOptionWithTargetPriority(priority []ValidTarget) WatchOption {
 	return func(o *WatchOptions) error {
 		o.targetPriority = priority
@@ -77,8 +126,16 @@ func (x *XClip) Watch(ctx context.Context, opt ...WatchOption) <-chan Selection
 
 	ch := make(chan Selection)
 
+	opts.status.update(func(s *WatchState) {
+		s.Selection = opts.clip
+		s.Running = true
+	})
+
 	go func() {
 		defer close(ch)
+		defer opts.status.update(func(s *WatchState) {
+			s.Running = false
+		})
 		initialCtx, cancel := context.WithTimeout(ctx, opts.frequency)
 
 		previous := bytes.NewBuffer([]byte{})
@@ -87,11 +144,12 @@ func (x *XClip) Watch(ctx context.Context, opt ...WatchOption) <-chan Selection
 			PasteOptionWithSelection(opts.clip),
 		}
 
-		monitorTarget, _ := findValidTarget(initialCtx, opts.monitorTargets, opts, x)
+		monitorTarget, _, _ := findValidTarget(initialCtx, opts.monitorTargets, opts, x)
 		if monitorTarget != ValidTargetUnknown {
 			err := x.Paste(initialCtx, previous, append(commonPasteOpts, PasteOptionWithTarget(monitorTarget))...)
 			if err != nil {
-				log.Fatalf("Failed to read inital clipboard: %v", err)
+				slog.Error("Failed to read inital clipboard", "selection", opts.clip, "error", err)
+				os.Exit(1)
 			}
 		}
 		cancel()
@@ -105,28 +163,36 @@ func (x *XClip) Watch(ctx context.Context, opt ...WatchOption) <-chan Selection
 					ctx, cancel := context.WithTimeout(ctx, opts.frequency)
 					defer cancel()
 
-					monitorTarget, allTargets := findValidTarget(ctx, opts.monitorTargets, opts, x)
+					opts.status.update(func(s *WatchState) {
+						s.LastPoll = time.Now()
+					})
+
+					monitorTarget, allTargets, err := findValidTarget(ctx, opts.monitorTargets, opts, x)
+					if err != nil {
+						opts.status.error(err)
+					}
 					if monitorTarget == ValidTargetUnknown {
 						return
 					}
 
 					current.Reset()
-					err := x.Paste(ctx, current, append(commonPasteOpts, PasteOptionWithTarget(monitorTarget))...)
+					err = x.Paste(ctx, current, append(commonPasteOpts, PasteOptionWithTarget(monitorTarget))...)
 					if err != nil {
-						log.Printf("Failed to read clipboard: %v", err)
+						slog.Warn("Failed to read clipboard", "selection", opts.clip, "error", err)
+						opts.status.error(err)
 						return
 					}
 					if bytes.Equal(previous.Bytes(), current.Bytes()) {
 						return
 					}
 
-					log.Printf("Detected change in clipboard %s", opts.clip)
+					slog.Debug("Detected change in clipboard", "selection", opts.clip)
 					previous.Reset()
 					current.WriteTo(previous)
 
 					withTarget := desiredTarget(allTargets, opts.targetPriority)
 					if withTarget == ValidTargetUnknown {
-						log.Printf("No valid target found to read, available targets: %v, valid targets: %v", allTargets, opts.targetPriority)
+						slog.Info("No valid target found to read", "selection", opts.clip, "available", allTargets, "valid", opts.targetPriority)
 						return
 					}
 					buf := bytes.NewBuffer([]byte{})
@@ -136,14 +202,19 @@ func (x *XClip) Watch(ctx context.Context, opt ...WatchOption) <-chan Selection
 						append(commonPasteOpts, PasteOptionWithTarget(withTarget))...,
 					)
 					if err != nil {
-						log.Printf("Failed to read clipboard: %v", err)
+						slog.Warn("Failed to read clipboard", "selection", opts.clip, "target", withTarget, "error", err)
+						opts.status.error(err)
 						return
 					}
 
+					opts.status.update(func(s *WatchState) {
+						s.LastChange = time.Now()
+					})
+
 					select {
 					case <-ctx.Done():
 						return
-					case ch <- NewSelection(buf.Bytes(), withTarget):
+					case ch <- NewSelection(buf.Bytes(), withTarget, opts.clip):
 					}
 				}()
 			}
@@ -152,20 +223,20 @@ func (x *XClip) Watch(ctx context.Context, opt ...WatchOption) <-chan Selection
 	return ch
 }
 
-func findValidTarget(ctx context.Context, targets []ValidTarget, opts *WatchOptions, x *XClip) (ValidTarget, []ValidTarget) {
+func findValidTarget(ctx context.Context, targets []ValidTarget, opts *WatchOptions, x *XClip) (ValidTarget, []ValidTarget, error) {
 	currentTargets, err := x.Targets(
 		ctx,
 		TargetsOptionWithSelection(opts.clip),
 	)
 	if err != nil {
-		return ValidTargetUnknown, nil
+		return ValidTargetUnknown, nil, err
 	}
 	for _, target := range opts.monitorTargets {
 		if slices.Contains(currentTargets, target) {
-			return target, currentTargets
+			return target, currentTargets, nil
 		}
 	}
-	return ValidTargetUnknown, currentTargets
+	return ValidTargetUnknown, currentTargets, nil
 }
 
 type ReadResult struct {
@@ -204,6 +275,25 @@ const (
 	ValidTargetImagePng                         ValidTarget = "image/png"
 )
 
+// ValidTargets are the known targets
+var ValidTargets = []ValidTarget{
+	ValidTargetTIMESTAMP,
+	ValidTargetTARGETS,
+	ValidTargetMULTIPLE,
+	ValidTargetUTF8_STRING,
+	ValidTargetCOMPOUND_TEXT,
+	ValidTargetTEXT,
+	ValidTargetSTRING,
+	ValidTargetTextPlainUTF8,
+	ValidTargetTextPlain,
+	ValidTargetSAVE_TARGETS,
+	ValidTargetxSpecialGnomeCopiedFiles,
+	ValidTargetApplicationVndPortalFiletransfer,
+	ValidTargetApplicationVndPortalFiles,
+	ValidTargetTextUriList,
+	ValidTargetImagePng,
+}
+
 func TargetsOptionWithSelection(selection ClipboardSelection) TargetsOption {
 	return func(o *TargetsOptions) {
 		o.Selection = selection
@@ -12,16 +12,21 @@ func TestWatch(t *testing.T) {
 		execer.AddCallN("xclip -o -target TARGETS -selection clipboard -silent", []byte(ValidTargetUTF8_STRING), 3)
 		execer.AddCall("xclip -o -selection clipboard -target UTF8_STRING -silent", []byte("test"))
 		execer.AddCall("xclip -o -selection clipboard -target UTF8_STRING -silent", []byte("test"))
-		execer.AddCall("xclip -o -selection clipboard -target UTF8_STRING -silent", []byte("new content"))
+		execer.AddCallN("xclip -o -selection clipboard -target UTF8_STRING -silent", []byte("new content"), 2)
 
 		xclip := XClip{
 			RunFn: execer.Run,
 		}
 
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		status := &WatchStatus{}
 		ch := xclip.Watch(
-			context.Background(),
+			ctx,
+			WatchOptionWithStatus(status),
 			WatchOptionWithClipboardSelection(ClipboardSelectionClipboard),
 			WatchOptionWithMonitorTargets([]ValidTarget{ValidTargetUTF8_STRING}),
+			WatchOptionWithTargetPriority([]ValidTarget{ValidTargetUTF8_STRING}),
 			WatchOptionWithFrequency(time.Millisecond), // Speed up the test
 		)
 		changes := <-ch
@@ -29,5 +34,24 @@ func TestWatch(t *testing.T) {
 		if string(changes.Content) != "new content" {
 			t.Errorf("Paste() = %v, want %v", changes.Content, "new content")
 		}
+		if changes.Clipboard != ClipboardSelectionClipboard {
+			t.Errorf("Clipboard = %v, want %v", changes.Clipboard, ClipboardSelectionClipboard)
+		}
+
+		state := status.State()
+		if !state.Running || state.Selection != ClipboardSelectionClipboard {
+			t.Errorf("State() = %+v, want running clipboard watch", state)
+		}
+		if state.LastChange.IsZero() || state.LastPoll.IsZero() {
+			t.Errorf("State() = %+v, want last poll and change to be recorded", state)
+		}
+
+		// Stop the watcher before the test returns, it keeps polling until then
+		cancel()
+		for range ch {
+		}
+		if status.State().Running {
+			t.Errorf("State() = %+v, want stopped watch", status.State())
+		}
 	})
 }